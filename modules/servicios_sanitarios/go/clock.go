@@ -0,0 +1,64 @@
+package servicios_sanitarios
+
+import "time"
+
+// Clock abstrae la obtención del instante actual, de modo que los tests
+// puedan inyectar una hora fija en lugar de depender de time.Now().
+type Clock interface {
+	Now() time.Time
+}
+
+// relojSistema es el Clock por defecto: delega en time.Now().
+type relojSistema struct{}
+
+func (relojSistema) Now() time.Time {
+	return time.Now()
+}
+
+// Config agrupa los parámetros de tiempo de un ServiciosSanitarios: la
+// zona horaria en la que se expresan sus timestamps y el reloj del que
+// se obtienen. El valor cero es válido: se completa con UTC y el reloj
+// del sistema vía conValoresPorDefecto.
+type Config struct {
+	Zona  *time.Location
+	Reloj Clock
+}
+
+// conValoresPorDefecto devuelve c con sus campos vacíos completados:
+// UTC como zona y relojSistema como reloj.
+func (c Config) conValoresPorDefecto() Config {
+	if c.Zona == nil {
+		c.Zona = time.UTC
+	}
+	if c.Reloj == nil {
+		c.Reloj = relojSistema{}
+	}
+	return c
+}
+
+// WithZona configura la zona horaria en la que ServiciosSanitarios
+// expresa sus timestamps (p.ej. "America/Santiago"). Si nombre no es una
+// zona IANA válida, se conserva UTC.
+func WithZona(nombre string) Option {
+	return func(s *ServiciosSanitarios) {
+		loc, err := time.LoadLocation(nombre)
+		if err != nil {
+			return
+		}
+		s.config.Zona = loc
+	}
+}
+
+// WithClock reemplaza el reloj del sistema por reloj, típicamente para
+// hacer determinista un test.
+func WithClock(reloj Clock) Option {
+	return func(s *ServiciosSanitarios) {
+		s.config.Reloj = reloj
+	}
+}
+
+// ahora obtiene el instante actual del reloj configurado, expresado en
+// la zona horaria configurada.
+func (s *ServiciosSanitarios) ahora() time.Time {
+	return s.config.Reloj.Now().In(s.config.Zona)
+}
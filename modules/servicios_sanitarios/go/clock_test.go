@@ -0,0 +1,66 @@
+package servicios_sanitarios
+
+import (
+	"testing"
+	"time"
+)
+
+// relojFijo es un Clock de prueba que siempre devuelve el mismo instante.
+type relojFijo struct {
+	instante time.Time
+}
+
+func (r relojFijo) Now() time.Time {
+	return r.instante
+}
+
+func TestWithClockFechaDeterminista(t *testing.T) {
+	instante := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	servicio := NewServiciosSanitarios("", WithClock(relojFijo{instante: instante}))
+
+	if !servicio.FechaCreacion.Equal(instante) {
+		t.Errorf("FechaCreacion esperada %v, obtenida %v", instante, servicio.FechaCreacion)
+	}
+
+	tarea, err := servicio.AgregarTarea("Tarea determinista", "", nil)
+	if err != nil {
+		t.Fatalf("Error al agregar tarea: %v", err)
+	}
+	if !tarea.FechaCreacion.Equal(instante) {
+		t.Errorf("FechaCreacion de la tarea esperada %v, obtenida %v", instante, tarea.FechaCreacion)
+	}
+
+	servicio.CompletarTarea(tarea.ID)
+	tareas := servicio.ListarTareas("completado", "")
+	if len(tareas) != 1 {
+		t.Fatalf("Debe haber 1 tarea completada, hay %d", len(tareas))
+	}
+	if tareas[0].FechaCompletado == nil || !tareas[0].FechaCompletado.Equal(instante) {
+		t.Errorf("FechaCompletado esperada %v, obtenida %v", instante, tareas[0].FechaCompletado)
+	}
+}
+
+func TestWithZonaConvierteTimestamps(t *testing.T) {
+	zona, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skipf("zona horaria no disponible en este entorno: %v", err)
+	}
+
+	instante := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	servicio := NewServiciosSanitarios("", WithClock(relojFijo{instante: instante}), WithZona("America/Santiago"))
+
+	if servicio.FechaCreacion.Location().String() != zona.String() {
+		t.Errorf("FechaCreacion debe estar en zona %s, está en %s", zona, servicio.FechaCreacion.Location())
+	}
+	if !servicio.FechaCreacion.Equal(instante) {
+		t.Error("FechaCreacion debe representar el mismo instante, solo con zona distinta")
+	}
+}
+
+func TestWithZonaInvalidaConservaUTC(t *testing.T) {
+	servicio := NewServiciosSanitarios("", WithZona("Zona/Inexistente"))
+
+	if servicio.FechaCreacion.Location().String() != time.UTC.String() {
+		t.Errorf("Con una zona inválida debe conservarse UTC, se usó %s", servicio.FechaCreacion.Location())
+	}
+}
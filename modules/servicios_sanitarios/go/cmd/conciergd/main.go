@@ -0,0 +1,150 @@
+// conciergd es el daemon de verificación de servicios_sanitarios: carga un
+// config.ScrapeConfig, agenda cada target con scheduler.Daemon y sirve sus
+// métricas Prometheus, hasta recibir una señal de apagado.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/client_golang/prometheus"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/config"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/observability"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/scheduler"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run contiene el cuerpo de main como una función que retorna un código de
+// salida en lugar de llamar a os.Exit directamente, para que los defers de
+// apagado (en particular, el del tracer provider) siempre se ejecuten.
+func run() int {
+	rutaConfig := flag.String("config", "scrape.yaml", "ruta al archivo de configuración de targets (YAML)")
+	direccionMetricas := flag.String("metrics-addr", ":9090", "dirección en la que servir /metrics")
+	webhookURL := flag.String("webhook-url", "", "URL a la que enviar un POST JSON cuando un target cambie")
+	mqttBroker := flag.String("mqtt-broker", "", "URL del broker MQTT (p.ej. tcp://localhost:1883) al que publicar los cambios")
+	mqttTopico := flag.String("mqtt-topic-prefix", "concierge/servicios-sanitarios", "prefijo de tópico MQTT bajo el que publicar, por target")
+	smtpServidor := flag.String("smtp-addr", "", "servidor SMTP (host:puerto) al que enviar avisos por correo")
+	smtpRemitente := flag.String("smtp-from", "", "dirección remitente de los correos de aviso")
+	smtpDestinatarios := flag.String("smtp-to", "", "direcciones destinatarias de los correos de aviso, separadas por coma")
+	smtpUsuario := flag.String("smtp-user", "", "usuario SMTP, si el servidor requiere autenticación")
+	smtpClave := flag.String("smtp-pass", "", "clave SMTP, si el servidor requiere autenticación")
+	flag.Parse()
+
+	logger := observability.NewLogger(os.Stderr)
+
+	apagarTracing, err := observability.ConfigurarTracerProvider(context.Background())
+	if err != nil {
+		logger.Error("error al configurar el tracer provider", "error", err)
+		return 1
+	}
+	defer func() {
+		ctxApagado, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := apagarTracing(ctxApagado); err != nil {
+			logger.Error("error al apagar el tracer provider", "error", err)
+		}
+	}()
+
+	cfg, err := config.CargarScrapeConfig(*rutaConfig)
+	if err != nil {
+		logger.Error("error al cargar configuración", "error", err)
+		return 1
+	}
+
+	registro := prometheus.NewRegistry()
+	verifier := ss.NewVerifier(*cfg, ss.WithVerifierLogger(logger))
+
+	var notifiers []scheduler.Notifier
+	if *webhookURL != "" {
+		notifiers = append(notifiers, scheduler.NewWebhookNotifier(*webhookURL))
+	}
+	if *mqttBroker != "" {
+		opciones := mqtt.NewClientOptions().AddBroker(*mqttBroker).SetClientID("conciergd")
+		cliente := mqtt.NewClient(opciones)
+		if token := cliente.Connect(); token.Wait() && token.Error() != nil {
+			logger.Error("error al conectar con el broker MQTT", "error", token.Error())
+			return 1
+		}
+		defer cliente.Disconnect(250)
+		notifiers = append(notifiers, scheduler.NewMQTTNotifier(cliente, *mqttTopico))
+	}
+	if *smtpServidor != "" {
+		var auth smtp.Auth
+		if *smtpUsuario != "" {
+			host := *smtpServidor
+			if i := strings.LastIndex(host, ":"); i != -1 {
+				host = host[:i]
+			}
+			auth = smtp.PlainAuth("", *smtpUsuario, *smtpClave, host)
+		}
+		var destinatarios []string
+		for _, d := range strings.Split(*smtpDestinatarios, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				destinatarios = append(destinatarios, d)
+			}
+		}
+		notifiers = append(notifiers, scheduler.NewEmailNotifier(*smtpServidor, auth, *smtpRemitente, destinatarios))
+	}
+
+	daemon := scheduler.NewDaemon(*cfg, verifier,
+		scheduler.WithNotifiers(notifiers...),
+		scheduler.WithMetrics(registro),
+		scheduler.WithLogger(logger),
+	)
+
+	if err := daemon.Start(); err != nil {
+		logger.Error("error al iniciar el scheduler", "error", err)
+		return 1
+	}
+
+	servidorMetricas := &http.Server{
+		Addr:    *direccionMetricas,
+		Handler: daemon.Handler(),
+	}
+	errMetricas := make(chan error, 1)
+	go func() {
+		if err := servidorMetricas.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errMetricas <- err
+			return
+		}
+		errMetricas <- nil
+	}()
+	logger.Info("sirviendo métricas", "addr", *direccionMetricas+"/metrics")
+
+	señales := make(chan os.Signal, 1)
+	signal.Notify(señales, os.Interrupt, syscall.SIGTERM)
+
+	codigoSalida := 0
+	select {
+	case <-señales:
+		logger.Info("apagando...")
+	case err := <-errMetricas:
+		logger.Error("error al servir métricas", "error", err)
+		codigoSalida = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := daemon.Stop(ctx); err != nil {
+		logger.Error("error al detener el scheduler", "error", err)
+	}
+	if err := servidorMetricas.Shutdown(ctx); err != nil {
+		logger.Error("error al detener el servidor de métricas", "error", err)
+	}
+
+	return codigoSalida
+}
@@ -0,0 +1,159 @@
+// Package config carga la configuración de verificación multi-target de
+// servicios_sanitarios, al estilo de los scrape_configs de Prometheus:
+// un YAML con una lista de targets, cada uno con su propia URL, regla de
+// extracción, cadencia y destino de almacenamiento.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/extract"
+)
+
+const (
+	intervaloPorDefecto  = 15 * time.Minute
+	timeoutPorDefecto    = 10 * time.Second
+	reintentosPorDefecto = 1
+)
+
+// ReglaExtraccion describe cómo ubicar, en la página de un target, el
+// enlace de interés (p.ej. "Tarifas vigentes"). Debe definirse
+// exactamente uno de Selector (CSS) o Regex.
+//
+// Deprecated: usa Target.Reglas, que admite una cadena de reglas CSS,
+// XPath, regex o JSONPath (ver el paquete extract) en lugar de un único
+// selector o regex.
+type ReglaExtraccion struct {
+	Selector string `yaml:"selector,omitempty"`
+	Regex    string `yaml:"regex,omitempty"`
+}
+
+// Target describe una fuente a verificar periódicamente.
+type Target struct {
+	Nombre     string          `yaml:"nombre"`
+	URL        string          `yaml:"url"`
+	Extraccion ReglaExtraccion `yaml:"extraccion,omitempty"`
+	// Reglas es la cadena de extract.Rule a aplicar sobre la página del
+	// target, en orden, para ubicar su enlace de interés. Reemplaza a
+	// Extraccion; un target define exactamente uno de los dos.
+	Reglas         []extract.Rule    `yaml:"reglas,omitempty"`
+	Intervalo      time.Duration     `yaml:"intervalo"`
+	Timeout        time.Duration     `yaml:"timeout"`
+	Reintentos     int               `yaml:"reintentos"`
+	Almacenamiento string            `yaml:"almacenamiento"`
+	Labels         map[string]string `yaml:"labels,omitempty"`
+	// Cron es la expresión cron (o el atajo "@every <duración>") que usa
+	// scheduler.Daemon para este target. Si queda vacío, el daemon agenda
+	// el target con "@every <Intervalo>".
+	Cron string `yaml:"cron,omitempty"`
+}
+
+// ReglasEfectivas devuelve t.Reglas si está definido, o lo sintetiza a
+// partir de t.Extraccion (deprecado) para que Verifier solo tenga que
+// conocer el formato de reglas nuevo.
+func (t *Target) ReglasEfectivas() []extract.Rule {
+	if len(t.Reglas) > 0 {
+		return t.Reglas
+	}
+
+	switch {
+	case t.Extraccion.Selector != "":
+		return []extract.Rule{{
+			Kind:             extract.KindCSS,
+			Expresion:        t.Extraccion.Selector,
+			Atributo:         "href",
+			PostProcesadores: []extract.PostProcesador{extract.PostResolverRelativa},
+		}}
+	case t.Extraccion.Regex != "":
+		return []extract.Rule{{
+			Kind:      extract.KindRegex,
+			Expresion: t.Extraccion.Regex,
+		}}
+	default:
+		return nil
+	}
+}
+
+// ScrapeConfig es la configuración de verificación multi-target: una
+// lista de targets (SISS, SEC, SERNAC, portales municipales, etc.).
+type ScrapeConfig struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// CargarScrapeConfig lee y parsea un ScrapeConfig desde rutaArchivo,
+// completa los valores por defecto de cada target y valida el resultado.
+func CargarScrapeConfig(rutaArchivo string) (*ScrapeConfig, error) {
+	datos, err := os.ReadFile(rutaArchivo)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer configuración: %w", err)
+	}
+
+	var cfg ScrapeConfig
+	if err := yaml.Unmarshal(datos, &cfg); err != nil {
+		return nil, fmt.Errorf("error al parsear configuración: %w", err)
+	}
+
+	for i := range cfg.Targets {
+		cfg.Targets[i].AplicarValoresPorDefecto()
+	}
+
+	if err := cfg.Validar(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// AplicarValoresPorDefecto completa Intervalo, Timeout y Reintentos con
+// sus valores por defecto cuando quedaron en su valor cero. CargarScrapeConfig
+// la invoca automáticamente para cada target cargado desde YAML; los
+// consumidores que construyan un Target a mano (p.ej. en tests) deben
+// invocarla ellos mismos.
+func (t *Target) AplicarValoresPorDefecto() {
+	if t.Intervalo == 0 {
+		t.Intervalo = intervaloPorDefecto
+	}
+	if t.Timeout == 0 {
+		t.Timeout = timeoutPorDefecto
+	}
+	if t.Reintentos == 0 {
+		t.Reintentos = reintentosPorDefecto
+	}
+}
+
+// Validar comprueba que cada target tenga nombre, URL y exactamente una
+// regla de extracción, y que los nombres de target no se repitan.
+func (c *ScrapeConfig) Validar() error {
+	vistos := make(map[string]bool, len(c.Targets))
+
+	for _, t := range c.Targets {
+		if t.Nombre == "" {
+			return fmt.Errorf("target sin nombre")
+		}
+		if vistos[t.Nombre] {
+			return fmt.Errorf("target duplicado: %s", t.Nombre)
+		}
+		vistos[t.Nombre] = true
+
+		if t.URL == "" {
+			return fmt.Errorf("target %q sin url", t.Nombre)
+		}
+
+		tieneExtraccionLegada := t.Extraccion.Selector != "" || t.Extraccion.Regex != ""
+		if len(t.Reglas) == 0 && !tieneExtraccionLegada {
+			return fmt.Errorf("target %q sin regla de extracción (reglas o extraccion)", t.Nombre)
+		}
+		if len(t.Reglas) > 0 && tieneExtraccionLegada {
+			return fmt.Errorf("target %q no puede definir reglas y extraccion a la vez", t.Nombre)
+		}
+		if t.Extraccion.Selector != "" && t.Extraccion.Regex != "" {
+			return fmt.Errorf("target %q no puede definir selector y regex a la vez", t.Nombre)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/extract"
+)
+
+func escribirConfigTemporal(t *testing.T, contenido string) string {
+	t.Helper()
+	ruta := filepath.Join(t.TempDir(), "scrape.yaml")
+	if err := os.WriteFile(ruta, []byte(contenido), 0644); err != nil {
+		t.Fatalf("error al escribir configuración de prueba: %v", err)
+	}
+	return ruta
+}
+
+func TestCargarScrapeConfigValoresPorDefecto(t *testing.T) {
+	ruta := escribirConfigTemporal(t, `
+targets:
+  - nombre: siss
+    url: https://www.siss.gob.cl
+    extraccion:
+      selector: a:contains("Tarifas vigentes")
+    almacenamiento: data/siss.json
+`)
+
+	cfg, err := CargarScrapeConfig(ruta)
+	if err != nil {
+		t.Fatalf("error al cargar configuración: %v", err)
+	}
+
+	if len(cfg.Targets) != 1 {
+		t.Fatalf("se esperaba 1 target, hay %d", len(cfg.Targets))
+	}
+
+	target := cfg.Targets[0]
+	if target.Intervalo != intervaloPorDefecto {
+		t.Errorf("Intervalo esperado %v, obtenido %v", intervaloPorDefecto, target.Intervalo)
+	}
+	if target.Timeout != timeoutPorDefecto {
+		t.Errorf("Timeout esperado %v, obtenido %v", timeoutPorDefecto, target.Timeout)
+	}
+	if target.Reintentos != reintentosPorDefecto {
+		t.Errorf("Reintentos esperado %d, obtenido %d", reintentosPorDefecto, target.Reintentos)
+	}
+}
+
+func TestCargarScrapeConfigRespetaValoresExplicitos(t *testing.T) {
+	ruta := escribirConfigTemporal(t, `
+targets:
+  - nombre: sec
+    url: https://www.sec.cl
+    extraccion:
+      regex: "tarifas-[0-9]+\\.pdf"
+    intervalo: 5m
+    timeout: 3s
+    reintentos: 3
+    almacenamiento: data/sec.json
+    labels:
+      region: metropolitana
+`)
+
+	cfg, err := CargarScrapeConfig(ruta)
+	if err != nil {
+		t.Fatalf("error al cargar configuración: %v", err)
+	}
+
+	target := cfg.Targets[0]
+	if target.Intervalo != 5*time.Minute {
+		t.Errorf("Intervalo esperado 5m, obtenido %v", target.Intervalo)
+	}
+	if target.Timeout != 3*time.Second {
+		t.Errorf("Timeout esperado 3s, obtenido %v", target.Timeout)
+	}
+	if target.Reintentos != 3 {
+		t.Errorf("Reintentos esperado 3, obtenido %d", target.Reintentos)
+	}
+	if target.Labels["region"] != "metropolitana" {
+		t.Errorf("Label 'region' esperado 'metropolitana', obtenido %q", target.Labels["region"])
+	}
+}
+
+func TestValidarTargetSinReglaDeExtraccion(t *testing.T) {
+	cfg := ScrapeConfig{Targets: []Target{
+		{Nombre: "invalido", URL: "https://example.cl", Almacenamiento: "data/invalido.json"},
+	}}
+
+	if err := cfg.Validar(); err == nil {
+		t.Error("debe fallar cuando el target no define selector ni regex")
+	}
+}
+
+func TestCargarScrapeConfigConReglas(t *testing.T) {
+	ruta := escribirConfigTemporal(t, `
+targets:
+  - nombre: sernac
+    url: https://www.sernac.cl
+    reglas:
+      - kind: css
+        expresion: "#tarifas"
+      - kind: css
+        expresion: a[href$=".pdf"]
+        atributo: href
+        post_procesadores: [resolve-relative]
+    almacenamiento: data/sernac.json
+`)
+
+	cfg, err := CargarScrapeConfig(ruta)
+	if err != nil {
+		t.Fatalf("error al cargar configuración: %v", err)
+	}
+
+	reglas := cfg.Targets[0].ReglasEfectivas()
+	if len(reglas) != 2 {
+		t.Fatalf("se esperaban 2 reglas, hay %d", len(reglas))
+	}
+	if reglas[1].Kind != extract.KindCSS || reglas[1].Atributo != "href" {
+		t.Errorf("segunda regla inesperada: %+v", reglas[1])
+	}
+}
+
+func TestTargetReglasEfectivasDesdeExtraccionLegada(t *testing.T) {
+	target := Target{Extraccion: ReglaExtraccion{Selector: `a:contains("Tarifas")`}}
+
+	reglas := target.ReglasEfectivas()
+	if len(reglas) != 1 || reglas[0].Kind != extract.KindCSS {
+		t.Fatalf("se esperaba 1 regla CSS sintetizada, se obtuvo %+v", reglas)
+	}
+}
+
+func TestValidarTargetReglasYExtraccionALaVez(t *testing.T) {
+	cfg := ScrapeConfig{Targets: []Target{
+		{
+			Nombre:     "invalido",
+			URL:        "https://example.cl",
+			Extraccion: ReglaExtraccion{Selector: "a"},
+			Reglas:     []extract.Rule{{Kind: extract.KindCSS, Expresion: "a"}},
+		},
+	}}
+
+	if err := cfg.Validar(); err == nil {
+		t.Error("debe fallar cuando el target define reglas y extraccion a la vez")
+	}
+}
+
+func TestValidarTargetDuplicado(t *testing.T) {
+	cfg := ScrapeConfig{Targets: []Target{
+		{Nombre: "siss", URL: "https://a.cl", Extraccion: ReglaExtraccion{Selector: "a"}},
+		{Nombre: "siss", URL: "https://b.cl", Extraccion: ReglaExtraccion{Selector: "a"}},
+	}}
+
+	if err := cfg.Validar(); err == nil {
+		t.Error("debe fallar cuando hay dos targets con el mismo nombre")
+	}
+}
+
+func TestCargarScrapeConfigArchivoInexistente(t *testing.T) {
+	if _, err := CargarScrapeConfig(filepath.Join(t.TempDir(), "no-existe.yaml")); err == nil {
+		t.Error("debe fallar si el archivo no existe")
+	}
+}
@@ -0,0 +1,29 @@
+package servicios_sanitarios
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerificarRedireccionURLContextCancelado(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := VerificarRedireccionURLContext(ctx, "https://www.siss.gob.cl")
+	if err == nil {
+		t.Error("Debe retornar error cuando el contexto ya está cancelado")
+	}
+}
+
+func TestVerificarSISSContextCancelado(t *testing.T) {
+	servicio := NewServiciosSanitarios("")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resultado := servicio.VerificarSISSContext(ctx, "")
+
+	if resultado.Exito {
+		t.Error("La verificación no debe tener éxito con el contexto cancelado")
+	}
+}
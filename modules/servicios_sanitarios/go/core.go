@@ -1,10 +1,29 @@
 package servicios_sanitarios
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/extract"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/metrics"
 )
 
+// tracer es el trace.Tracer por defecto de servicios_sanitarios. Si
+// ninguna opción configuró un proveedor de OpenTelemetry (p.ej. vía
+// observability.ConfigurarTracerProvider), otel.Tracer usa el proveedor
+// global no-op, por lo que instrumentar el código no agrega tráfico de
+// red ni overhead perceptible cuando nadie está exportando trazas.
+var tracer = otel.Tracer("github.com/Geek-MD/Concierge/modules/servicios_sanitarios")
+
 // Tarea representa una tarea en el sistema
 type Tarea struct {
 	ID              string                 `json:"id"`
@@ -14,28 +33,136 @@ type Tarea struct {
 	FechaCreacion   time.Time              `json:"fecha_creacion"`
 	FechaCompletado *time.Time             `json:"fecha_completado,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata"`
+	Recordatorio    *Recordatorio          `json:"recordatorio,omitempty"`
+}
+
+// Recordatorio representa un aviso (VALARM) asociado a una tarea. Puede
+// dispararse en un instante absoluto (Momento) o relativo a la fecha de
+// creación de la tarea (Offset, típicamente negativo, p.ej. -1h).
+type Recordatorio struct {
+	Momento     *time.Time     `json:"momento,omitempty"`
+	Offset      *time.Duration `json:"offset,omitempty"`
+	Descripcion string         `json:"descripcion,omitempty"`
 }
 
 // ServiciosSanitarios es la clase principal para el manejo de servicios sanitarios
 type ServiciosSanitarios struct {
-	Nombre        string    `json:"nombre"`
-	ID            string    `json:"id"`
-	FechaCreacion time.Time `json:"fecha_creacion"`
-	Tareas        []Tarea   `json:"tareas"`
-	activo        bool
+	Nombre          string    `json:"nombre"`
+	ID              string    `json:"id"`
+	FechaCreacion   time.Time `json:"fecha_creacion"`
+	SelectorTarifas string    `json:"-"`
+	activo          bool
+	store           TareaStore
+	verificaciones  Store
+	metrics         *metrics.Collectors
+	config          Config
+	logger          *slog.Logger
+	tracer          trace.Tracer
+}
+
+// Option configura aspectos opcionales de un ServiciosSanitarios en su
+// construcción.
+type Option func(*ServiciosSanitarios)
+
+// WithMetrics habilita los colectores Prometheus del módulo, registrados
+// en reg (un *prometheus.Registry, o nil para crear uno nuevo). Sin esta
+// opción, ServiciosSanitarios funciona igual pero no expone métricas.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(s *ServiciosSanitarios) {
+		s.metrics = metrics.NewCollectors(reg)
+	}
+}
+
+// WithLogger reemplaza el *slog.Logger que VerificarSISSContext usa para
+// narrar su progreso (redirección resuelta, extracción, diff, guardado).
+// Sin esta opción se usa slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *ServiciosSanitarios) {
+		s.logger = logger
+	}
+}
+
+// WithTracer reemplaza el trace.Tracer que VerificarSISSContext usa para
+// instrumentar su ciclo de verificación. Sin esta opción se usa el
+// tracer por defecto del paquete (ver la variable tracer), respaldado
+// por el proveedor global de OpenTelemetry.
+func WithTracer(t trace.Tracer) Option {
+	return func(s *ServiciosSanitarios) {
+		s.tracer = t
+	}
+}
+
+// Handler devuelve el http.Handler de Prometheus del módulo. Es nil si
+// la instancia no se creó con WithMetrics.
+func (s *ServiciosSanitarios) Handler() http.Handler {
+	if s.metrics == nil {
+		return nil
+	}
+	return s.metrics.Handler()
+}
+
+// ConfigurarSelectorTarifas cambia el selector CSS que VerificarSISS usa
+// para ubicar el enlace de "Tarifas vigentes", de modo que un cambio de
+// diagramación del sitio no requiera tocar código.
+func (s *ServiciosSanitarios) ConfigurarSelectorTarifas(selector string) {
+	s.SelectorTarifas = selector
 }
 
 // NewServiciosSanitarios crea una nueva instancia de ServiciosSanitarios
-func NewServiciosSanitarios(nombre string) *ServiciosSanitarios {
+// respaldada por un MemoriaTareaStore. Para usar otro backend (SQLite,
+// Postgres, ...) usa NewServiciosSanitariosConStore.
+func NewServiciosSanitarios(nombre string, opts ...Option) *ServiciosSanitarios {
+	return NewServiciosSanitariosConStore(nombre, NewMemoriaTareaStore(), opts...)
+}
+
+// NewServiciosSanitariosConStore crea una nueva instancia de
+// ServiciosSanitarios respaldada por store, lo que permite reemplazar el
+// almacenamiento en memoria por un backend durable.
+func NewServiciosSanitariosConStore(nombre string, store TareaStore, opts ...Option) *ServiciosSanitarios {
 	if nombre == "" {
 		nombre = "ServiciosSanitarios"
 	}
-	return &ServiciosSanitarios{
-		Nombre:        nombre,
-		ID:            GenerateID(),
-		FechaCreacion: time.Now(),
-		Tareas:        []Tarea{},
-		activo:        true,
+	s := &ServiciosSanitarios{
+		Nombre:          nombre,
+		SelectorTarifas: selectorTarifasPorDefecto,
+		ID:              GenerateID(),
+		activo:          true,
+		store:           store,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.config = s.config.conValoresPorDefecto()
+	s.FechaCreacion = s.ahora()
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	if s.tracer == nil {
+		s.tracer = tracer
+	}
+
+	if s.metrics != nil {
+		s.metrics.ActualizarModuloActivo(s.activo)
+		s.sincronizarMetricasDeTareas()
+	}
+
+	return s
+}
+
+// sincronizarMetricasDeTareas inicializa concierge_tareas_total con las
+// tareas ya existentes en store, para que un backend durable (SQLite,
+// Postgres) no arranque reportando un backlog vacío hasta que se cree o
+// complete la primera tarea del proceso actual.
+func (s *ServiciosSanitarios) sincronizarMetricasDeTareas() {
+	tareas, err := s.store.List("", "")
+	if err != nil {
+		s.logger.Warn("no se pudieron sincronizar las métricas de tareas desde el store", "error", err)
+		return
+	}
+
+	for _, tarea := range tareas {
+		s.metrics.IncrementarTareasTotal(tarea.Estado, tarea.Prioridad)
 	}
 }
 
@@ -58,43 +185,60 @@ func (s *ServiciosSanitarios) AgregarTarea(descripcion, prioridad string, metada
 		Descripcion:     descripcion,
 		Prioridad:       prioridad,
 		Estado:          "pendiente",
-		FechaCreacion:   time.Now(),
+		FechaCreacion:   s.ahora(),
 		FechaCompletado: nil,
 		Metadata:        metadata,
 	}
 
-	s.Tareas = append(s.Tareas, tarea)
+	if err := s.store.Add(tarea); err != nil {
+		return nil, fmt.Errorf("error al guardar la tarea: %w", err)
+	}
+
+	if s.metrics != nil {
+		s.metrics.RegistrarTareaCreada()
+		s.metrics.IncrementarTareasTotal(tarea.Estado, tarea.Prioridad)
+	}
+
 	return &tarea, nil
 }
 
 // ListarTareas lista las tareas registradas, opcionalmente filtradas
 func (s *ServiciosSanitarios) ListarTareas(filtroEstado, filtroPrioridad string) []Tarea {
-	var tareasFiltradas []Tarea
-
-	for _, tarea := range s.Tareas {
-		if filtroEstado != "" && tarea.Estado != filtroEstado {
-			continue
-		}
-		if filtroPrioridad != "" && tarea.Prioridad != filtroPrioridad {
-			continue
-		}
-		tareasFiltradas = append(tareasFiltradas, tarea)
+	tareas, err := s.store.List(filtroEstado, filtroPrioridad)
+	if err != nil {
+		return nil
 	}
+	return tareas
+}
 
-	return tareasFiltradas
+// ObtenerTarea busca una tarea por ID.
+func (s *ServiciosSanitarios) ObtenerTarea(id string) (Tarea, bool, error) {
+	return s.store.Get(id)
 }
 
 // CompletarTarea marca una tarea como completada
 func (s *ServiciosSanitarios) CompletarTarea(tareaID string) bool {
-	for i := range s.Tareas {
-		if s.Tareas[i].ID == tareaID {
-			s.Tareas[i].Estado = "completado"
-			now := time.Now()
-			s.Tareas[i].FechaCompletado = &now
-			return true
+	var prioridad string
+	if s.metrics != nil {
+		if tarea, ok, err := s.store.Get(tareaID); err == nil && ok {
+			prioridad = tarea.Prioridad
 		}
 	}
-	return false
+
+	completado, err := s.store.Complete(tareaID, s.ahora())
+	if err != nil {
+		return false
+	}
+
+	if completado && s.metrics != nil {
+		s.metrics.RegistrarTareaCompletada()
+		if prioridad != "" {
+			s.metrics.DecrementarTareasTotal("pendiente", prioridad)
+			s.metrics.IncrementarTareasTotal("completado", prioridad)
+		}
+	}
+
+	return completado
 }
 
 // PorPrioridad representa el conteo de tareas por prioridad
@@ -117,38 +261,13 @@ type Estadisticas struct {
 
 // ObtenerEstadisticas obtiene estadísticas sobre las tareas del módulo
 func (s *ServiciosSanitarios) ObtenerEstadisticas() Estadisticas {
-	stats := Estadisticas{
-		Total:               len(s.Tareas),
-		Pendientes:          0,
-		Completadas:         0,
-		ModuloActivo:        s.activo,
-		FechaCreacionModulo: FormatTimestamp(s.FechaCreacion),
-		PorPrioridad: PorPrioridad{
-			Baja:    0,
-			Media:   0,
-			Alta:    0,
-			Critica: 0,
-		},
+	stats, err := s.store.Stats()
+	if err != nil {
+		stats = Estadisticas{}
 	}
 
-	for _, tarea := range s.Tareas {
-		if tarea.Estado == "pendiente" {
-			stats.Pendientes++
-		} else if tarea.Estado == "completado" {
-			stats.Completadas++
-		}
-
-		switch tarea.Prioridad {
-		case "baja":
-			stats.PorPrioridad.Baja++
-		case "media":
-			stats.PorPrioridad.Media++
-		case "alta":
-			stats.PorPrioridad.Alta++
-		case "critica":
-			stats.PorPrioridad.Critica++
-		}
-	}
+	stats.ModuloActivo = s.activo
+	stats.FechaCreacionModulo = FormatTimestamp(s.FechaCreacion)
 
 	return stats
 }
@@ -164,23 +283,34 @@ type Info struct {
 
 // ObtenerInfo obtiene información general del módulo
 func (s *ServiciosSanitarios) ObtenerInfo() Info {
+	total, err := s.store.Count()
+	if err != nil {
+		total = 0
+	}
+
 	return Info{
 		Nombre:        s.Nombre,
 		ID:            s.ID,
 		FechaCreacion: FormatTimestamp(s.FechaCreacion),
 		Activo:        s.activo,
-		TotalTareas:   len(s.Tareas),
+		TotalTareas:   total,
 	}
 }
 
 // Activar activa el módulo
 func (s *ServiciosSanitarios) Activar() {
 	s.activo = true
+	if s.metrics != nil {
+		s.metrics.ActualizarModuloActivo(true)
+	}
 }
 
 // Desactivar desactiva el módulo
 func (s *ServiciosSanitarios) Desactivar() {
 	s.activo = false
+	if s.metrics != nil {
+		s.metrics.ActualizarModuloActivo(false)
+	}
 }
 
 // EstaActivo verifica si el módulo está activo
@@ -188,50 +318,124 @@ func (s *ServiciosSanitarios) EstaActivo() bool {
 	return s.activo
 }
 
-// EntradaHistorial representa una entrada en el historial de cambios
+// EntradaHistorial representa una entrada en el historial de cambios.
+// Zona guarda el nombre IANA de la zona horaria en la que se expresaba
+// Timestamp al momento de registrarla, para que siga siendo
+// interpretable aunque la zona configurada cambie más adelante.
 type EntradaHistorial struct {
 	URLFinal           string `json:"url_final"`
 	URLTarifasVigentes string `json:"url_tarifas_vigentes"`
 	Timestamp          string `json:"timestamp"`
+	Zona               string `json:"zona"`
 }
 
-// DatosVerificacionSISS representa los datos guardados de verificación SISS
+// DatosVerificacionSISS representa los datos guardados de verificación
+// SISS, o de cualquier target de un config.ScrapeConfig verificado por
+// Verifier (que reutiliza este mismo formato de historial). Labels
+// persiste las etiquetas del target, si las tenía, para que los
+// consumidores del JSON puedan filtrar o agrupar resultados.
 type DatosVerificacionSISS struct {
 	URLOriginal        string             `json:"url_original"`
 	URLFinal           string             `json:"url_final"`
 	URLTarifasVigentes string             `json:"url_tarifas_vigentes"`
 	Timestamp          string             `json:"timestamp"`
+	Zona               string             `json:"zona"`
 	Verificado         bool               `json:"verificado"`
 	Historial          []EntradaHistorial `json:"historial"`
+	Labels             map[string]string  `json:"labels,omitempty"`
+	// Extraccion registra qué regla de extracción (ver el paquete
+	// extract) produjo URLTarifasVigentes y el texto crudo que
+	// coincidió, para poder auditar el resultado sin reproducir la
+	// cadena de reglas. Queda nil si la extracción falló.
+	Extraccion *extract.Resultado `json:"extraccion,omitempty"`
 }
 
 // ResultadoVerificacionSISS representa el resultado de una verificación SISS
 type ResultadoVerificacionSISS struct {
-	Exito              bool            `json:"exito"`
-	URLOriginal        string          `json:"url_original"`
-	URLFinal           string          `json:"url_final,omitempty"`
-	URLTarifasVigentes string          `json:"url_tarifas_vigentes,omitempty"`
-	Timestamp          string          `json:"timestamp"`
-	Archivo            string          `json:"archivo,omitempty"`
-	Guardado           bool            `json:"guardado"`
-	EsPrimeraVez       bool            `json:"es_primera_vez"`
-	Cambios            map[string]bool `json:"cambios,omitempty"`
-	Mensaje            string          `json:"mensaje"`
-	Error              string          `json:"error,omitempty"`
+	Exito              bool   `json:"exito"`
+	URLOriginal        string `json:"url_original"`
+	URLFinal           string `json:"url_final,omitempty"`
+	URLTarifasVigentes string `json:"url_tarifas_vigentes,omitempty"`
+	// Extraccion registra qué regla de extracción produjo
+	// URLTarifasVigentes y el texto crudo que coincidió, para poder
+	// auditar el resultado sin reproducir la cadena de reglas. Queda
+	// nil si la extracción falló.
+	Extraccion   *extract.Resultado `json:"extraccion,omitempty"`
+	Timestamp    string             `json:"timestamp"`
+	Archivo      string             `json:"archivo,omitempty"`
+	Guardado     bool               `json:"guardado"`
+	EsPrimeraVez bool               `json:"es_primera_vez"`
+	Cambios      map[string]bool    `json:"cambios,omitempty"`
+	Mensaje      string             `json:"mensaje"`
+	Error        string             `json:"error,omitempty"`
 }
 
-// VerificarSISS verifica la URL de redirección de la web de SISS y la guarda en JSON
+// camposCambiados devuelve los nombres de los campos marcados como
+// cambiados en cambios, en un orden estable, para usarlos como el
+// atributo "changed_fields" de una traza.
+func camposCambiados(cambios map[string]bool) []string {
+	var campos []string
+	for _, campo := range []string{"url_final", "url_tarifas_vigentes"} {
+		if cambios[campo] {
+			campos = append(campos, campo)
+		}
+	}
+	return campos
+}
+
+// VerificarSISS verifica la URL de redirección de la web de SISS y la guarda en JSON.
+//
+// Deprecated: usa VerificarSISSContext con un context.WithTimeout, que
+// permite cancelar la verificación (HTTP, extracción y escritura del
+// archivo) desde el llamador.
 func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerificacionSISS {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return s.VerificarSISSContext(ctx, rutaSalida)
+}
+
+// VerificarSISSContext es la variante de VerificarSISS que acepta un
+// context.Context para cancelación, en lugar de un timeout fijo. ctx.Done()
+// se respeta durante la solicitud HTTP, la cadena de redirecciones, el
+// parseo del HTML y la lectura/escritura del archivo de resultados.
+func (s *ServiciosSanitarios) VerificarSISSContext(ctx context.Context, rutaSalida string) (resultado ResultadoVerificacionSISS) {
+	ctx, span := s.tracer.Start(ctx, "VerificarSISS", trace.WithAttributes(attribute.String("target", "siss")))
+	defer func() {
+		span.SetAttributes(
+			attribute.String("url.original", resultado.URLOriginal),
+			attribute.String("url.final", resultado.URLFinal),
+			attribute.StringSlice("changed_fields", camposCambiados(resultado.Cambios)),
+		)
+		if resultado.Error != "" {
+			span.SetStatus(codes.Error, resultado.Error)
+		}
+		span.End()
+	}()
+
+	if s.metrics != nil {
+		inicio := time.Now()
+		defer func() {
+			s.metrics.RegistrarVerificacionSISS(time.Since(inicio).Seconds(), resultado.Exito)
+			for campo, cambio := range resultado.Cambios {
+				if cambio {
+					s.metrics.RegistrarCambioDetectado(campo)
+				}
+			}
+		}()
+	}
+
 	if rutaSalida == "" {
 		rutaSalida = "data/siss_url.json"
 	}
 
 	urlSISS := "https://www.siss.gob.cl"
-	timestamp := time.Now()
+	timestamp := s.ahora()
 
 	// Verificar redirección
-	urlFinal, err := VerificarRedireccionURL(urlSISS, 10)
+	urlFinal, err := VerificarRedireccionURLContext(ctx, urlSISS)
 	if err != nil {
+		s.logger.WarnContext(ctx, "no se pudo obtener la url de redirección", "url.original", urlSISS, "error", err)
 		return ResultadoVerificacionSISS{
 			Exito:       false,
 			URLOriginal: urlSISS,
@@ -239,20 +443,50 @@ func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerifica
 			Error:       fmt.Sprintf("No se pudo obtener la URL de redirección: %v", err),
 		}
 	}
+	s.logger.DebugContext(ctx, "redirección resuelta", "url.original", urlSISS, "url.final", urlFinal)
+
+	// Extraer URL de tarifas usando el selector CSS configurado
+	reglaTarifas := []extract.Rule{{
+		Kind:             extract.KindCSS,
+		Expresion:        s.SelectorTarifas,
+		Atributo:         "href",
+		PostProcesadores: []extract.PostProcesador{extract.PostResolverRelativa},
+	}}
+	urlTarifas := ""
+	var extraccion *extract.Resultado
+	if resultadoExtraccion, err := extract.NewExtractor().AplicarContext(ctx, urlFinal, reglaTarifas); err == nil {
+		urlTarifas = resultadoExtraccion.Valor
+		extraccion = &resultadoExtraccion
+		s.logger.DebugContext(ctx, "url de tarifas vigentes extraída", "url.tarifas_vigentes", urlTarifas)
+	} else {
+		s.logger.DebugContext(ctx, "no se pudo extraer la url de tarifas vigentes", "error", err)
+	}
 
-	// Extraer URL de "Tarifas vigentes"
-	urlTarifas, err := ExtraerURLPorTexto(urlFinal, "Tarifas vigentes", 10)
-	if err != nil {
-		// No es un error fatal, continuamos sin la URL de tarifas
-		urlTarifas = ""
+	if err := ctx.Err(); err != nil {
+		return ResultadoVerificacionSISS{
+			Exito:       false,
+			URLOriginal: urlSISS,
+			URLFinal:    urlFinal,
+			Timestamp:   FormatTimestamp(timestamp),
+			Error:       fmt.Sprintf("verificación cancelada: %v", err),
+		}
 	}
 
-	// Cargar datos previos si existen
+	// Cargar datos previos si existen. Si el módulo se configuró con
+	// WithStore, el backend durable reemplaza al archivo JSON.
 	var datosPrevios DatosVerificacionSISS
-	errCarga := CargarJSON(rutaSalida, &datosPrevios)
-	esPrimeraVez := errCarga != nil
+	var esPrimeraVez bool
+	if s.verificaciones != nil {
+		var ok bool
+		datosPrevios, ok, err = s.verificaciones.LoadLatest(ctx, rutaSalida)
+		esPrimeraVez = !ok || err != nil
+	} else {
+		errCarga := CargarJSON(rutaSalida, &datosPrevios)
+		esPrimeraVez = errCarga != nil
+	}
 
 	// Verificar si hay cambios
+	_, diffSpan := s.tracer.Start(ctx, "diff")
 	urlFinalCambio := false
 	urlTarifasCambio := false
 
@@ -262,6 +496,11 @@ func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerifica
 	}
 
 	hayCambios := esPrimeraVez || urlFinalCambio || urlTarifasCambio
+	diffSpan.SetAttributes(attribute.StringSlice("changed_fields", camposCambiados(map[string]bool{
+		"url_final":            urlFinalCambio,
+		"url_tarifas_vigentes": urlTarifasCambio,
+	})))
+	diffSpan.End()
 
 	// Solo guardar si hay cambios
 	guardado := false
@@ -278,6 +517,7 @@ func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerifica
 				URLFinal:           datosPrevios.URLFinal,
 				URLTarifasVigentes: datosPrevios.URLTarifasVigentes,
 				Timestamp:          datosPrevios.Timestamp,
+				Zona:               datosPrevios.Zona,
 			}
 			historial = append(historial, entradaHistorial)
 		}
@@ -288,13 +528,28 @@ func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerifica
 			URLFinal:           urlFinal,
 			URLTarifasVigentes: urlTarifas,
 			Timestamp:          FormatTimestamp(timestamp),
+			Zona:               s.config.Zona.String(),
 			Verificado:         true,
 			Historial:          historial,
+			Extraccion:         extraccion,
 		}
 
-		// Guardar en JSON
-		if err := GuardarJSON(datos, rutaSalida); err == nil {
-			guardado = true
+		// Guardar, salvo que el contexto ya se haya cancelado
+		if ctx.Err() == nil {
+			_, storeSpan := s.tracer.Start(ctx, "store.write", trace.WithAttributes(attribute.String("store.path", rutaSalida)))
+			var errGuardar error
+			if s.verificaciones != nil {
+				errGuardar = s.verificaciones.SaveVerificacion(ctx, rutaSalida, datos)
+			} else {
+				errGuardar = GuardarJSON(datos, rutaSalida)
+			}
+			if errGuardar == nil {
+				guardado = true
+			} else {
+				storeSpan.RecordError(errGuardar)
+				s.logger.WarnContext(ctx, "no se pudieron guardar los datos de verificación", "archivo", rutaSalida, "error", errGuardar)
+			}
+			storeSpan.End()
 		}
 	}
 
@@ -310,6 +565,7 @@ func (s *ServiciosSanitarios) VerificarSISS(rutaSalida string) ResultadoVerifica
 		URLOriginal:        urlSISS,
 		URLFinal:           urlFinal,
 		URLTarifasVigentes: urlTarifas,
+		Extraccion:         extraccion,
 		Timestamp:          FormatTimestamp(timestamp),
 		Archivo:            rutaSalida,
 		Guardado:           guardado,
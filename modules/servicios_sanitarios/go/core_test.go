@@ -16,8 +16,8 @@ func TestNewServiciosSanitarios(t *testing.T) {
 		t.Error("ID no debe estar vacío")
 	}
 
-	if len(servicio.Tareas) != 0 {
-		t.Errorf("Tareas debe estar vacío, tiene %d elementos", len(servicio.Tareas))
+	if len(servicio.ListarTareas("", "")) != 0 {
+		t.Errorf("Tareas debe estar vacío, tiene %d elementos", len(servicio.ListarTareas("", "")))
 	}
 
 	if !servicio.EstaActivo() {
@@ -58,8 +58,8 @@ func TestAgregarTareaBasica(t *testing.T) {
 		t.Error("ID de tarea no debe estar vacío")
 	}
 
-	if len(servicio.Tareas) != 1 {
-		t.Errorf("Debe haber 1 tarea, hay %d", len(servicio.Tareas))
+	if len(servicio.ListarTareas("", "")) != 1 {
+		t.Errorf("Debe haber 1 tarea, hay %d", len(servicio.ListarTareas("", "")))
 	}
 }
 
@@ -162,11 +162,12 @@ func TestCompletarTarea(t *testing.T) {
 		t.Error("CompletarTarea debe retornar true")
 	}
 
-	// Buscar la tarea en el slice
+	// Buscar la tarea entre las listadas
 	var tareaCompletada *Tarea
-	for i := range servicio.Tareas {
-		if servicio.Tareas[i].ID == tarea.ID {
-			tareaCompletada = &servicio.Tareas[i]
+	tareas := servicio.ListarTareas("", "")
+	for i := range tareas {
+		if tareas[i].ID == tarea.ID {
+			tareaCompletada = &tareas[i]
 			break
 		}
 	}
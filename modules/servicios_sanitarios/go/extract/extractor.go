@@ -0,0 +1,282 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/antchfx/htmlquery"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/html"
+)
+
+// tracer es el trace.Tracer de extract. Vive en su propio paquete (en
+// lugar de reusar el del paquete raíz) para que extract no dependa de
+// servicios_sanitarios; ver el comentario de resolverURLRelativa más
+// abajo sobre la misma restricción.
+var tracer = otel.Tracer("github.com/Geek-MD/Concierge/modules/servicios_sanitarios/extract")
+
+// Extractor descarga la página de un target y le aplica una cadena de
+// Rule, en orden, para ubicar el dato de interés.
+type Extractor struct {
+	cliente *http.Client
+}
+
+// NewExtractor crea un Extractor que descarga páginas con el cliente
+// HTTP por defecto.
+func NewExtractor() *Extractor {
+	return &Extractor{cliente: http.DefaultClient}
+}
+
+// AplicarContext descarga urlBase y ejecuta reglas en orden, devolviendo
+// el Resultado de la última regla de la cadena. Cada regla falla de
+// forma explícita (incluyendo su posición en el mensaje) en lugar de
+// devolver un valor vacío, para que un error de configuración de un
+// target no se confunda con "la página no tenía cambios".
+func (e *Extractor) AplicarContext(ctx context.Context, urlBase string, reglas []Rule) (Resultado, error) {
+	cuerpo, err := e.obtenerCuerpo(ctx, urlBase)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	_, span := tracer.Start(ctx, "extract.apply", trace.WithAttributes(attribute.Int("reglas", len(reglas))))
+	defer span.End()
+
+	resultado, err := Aplicar(urlBase, cuerpo, reglas)
+	if err != nil {
+		span.RecordError(err)
+		return Resultado{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("regla_indice", resultado.ReglaIndice),
+		attribute.String("regla_kind", string(resultado.ReglaKind)),
+	)
+
+	return resultado, nil
+}
+
+func (e *Extractor) obtenerCuerpo(ctx context.Context, urlBase string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "extract.fetch", trace.WithAttributes(attribute.String("url.original", urlBase)))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlBase, nil)
+	if err != nil {
+		return nil, fmt.Errorf("extract: error al construir la solicitud: %w", err)
+	}
+
+	resp, err := e.cliente.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("extract: error al obtener página: %w", err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("extract: código de estado HTTP: %d", resp.StatusCode)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	cuerpo, err := io.ReadAll(resp.Body)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("extract: error al leer cuerpo de la respuesta: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("bytes_read", len(cuerpo)))
+
+	return cuerpo, nil
+}
+
+// Aplicar ejecuta reglas sobre cuerpo (ya descargado, sin red), narrowing
+// el alcance de una regla CSS o XPath a la siguiente, y pasando el valor
+// de cada regla como entrada de la regex o jsonpath que la siga. urlBase
+// solo se usa para el post-procesador resolve-relative.
+func Aplicar(urlBase string, cuerpo []byte, reglas []Rule) (resultado Resultado, err error) {
+	if len(reglas) == 0 {
+		return Resultado{}, fmt.Errorf("extract: no hay reglas para aplicar")
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(cuerpo))
+	if err != nil {
+		return Resultado{}, fmt.Errorf("extract: error al parsear HTML: %w", err)
+	}
+
+	seleccionActual := doc.Selection
+	textoActual := string(cuerpo)
+
+	for i, regla := range reglas {
+		var textoCrudo string
+
+		switch regla.Kind {
+		case KindCSS:
+			sel := seleccionActual.Find(regla.Expresion)
+			if sel.Length() == 0 {
+				return Resultado{}, fmt.Errorf("extract: regla %d (css): sin coincidencias para %q", i, regla.Expresion)
+			}
+			valorSel, ok := valorDeSeleccion(sel, regla.Atributo)
+			if !ok {
+				return Resultado{}, fmt.Errorf("extract: regla %d (css): ningún elemento para %q tiene el atributo %q", i, regla.Expresion, regla.Atributo)
+			}
+			seleccionActual = sel
+			textoCrudo = valorSel
+		case KindXPath:
+			nodos, err := aplicarXPath(seleccionActual.Nodes, regla.Expresion)
+			if err != nil {
+				return Resultado{}, fmt.Errorf("extract: regla %d (xpath): %w", i, err)
+			}
+			if len(nodos) == 0 {
+				return Resultado{}, fmt.Errorf("extract: regla %d (xpath): sin coincidencias para %q", i, regla.Expresion)
+			}
+			nodo, valorNodo, ok := valorDeNodos(nodos, regla.Atributo)
+			if !ok {
+				return Resultado{}, fmt.Errorf("extract: regla %d (xpath): ningún nodo para %q tiene el atributo %q", i, regla.Expresion, regla.Atributo)
+			}
+			seleccionActual = goquery.NewDocumentFromNode(nodo).Selection
+			textoCrudo = valorNodo
+		case KindRegex:
+			re, err := regexp.Compile(regla.Expresion)
+			if err != nil {
+				return Resultado{}, fmt.Errorf("extract: regla %d (regex): expresión inválida: %w", i, err)
+			}
+			coincidencia := re.FindString(textoActual)
+			if coincidencia == "" {
+				return Resultado{}, fmt.Errorf("extract: regla %d (regex): sin coincidencias para %q", i, regla.Expresion)
+			}
+			textoCrudo = coincidencia
+		case KindJSONPath:
+			valorJSON, err := aplicarJSONPath(textoActual, regla.Expresion)
+			if err != nil {
+				return Resultado{}, fmt.Errorf("extract: regla %d (jsonpath): %w", i, err)
+			}
+			textoCrudo = valorJSON
+		default:
+			return Resultado{}, fmt.Errorf("extract: regla %d: tipo desconocido %q", i, regla.Kind)
+		}
+
+		valor := textoCrudo
+		for _, post := range regla.PostProcesadores {
+			valor, err = aplicarPostProcesador(post, valor, urlBase)
+			if err != nil {
+				return Resultado{}, fmt.Errorf("extract: regla %d: %w", i, err)
+			}
+		}
+		textoActual = valor
+
+		resultado = Resultado{
+			ReglaIndice: i,
+			ReglaKind:   regla.Kind,
+			Expresion:   regla.Expresion,
+			TextoCrudo:  textoCrudo,
+			Valor:       valor,
+		}
+	}
+
+	return resultado, nil
+}
+
+func aplicarXPath(raices []*html.Node, expresion string) ([]*html.Node, error) {
+	var resultado []*html.Node
+	for _, raiz := range raices {
+		nodos, err := htmlquery.QueryAll(raiz, expresion)
+		if err != nil {
+			return nil, fmt.Errorf("expresión inválida: %w", err)
+		}
+		resultado = append(resultado, nodos...)
+	}
+	return resultado, nil
+}
+
+// valorDeSeleccion devuelve el texto (o el valor de atributo, si se pide
+// uno) del primer elemento de sel. Cuando se pide un atributo, se salta
+// los elementos que no lo tienen en lugar de tomar el primero de sel sin
+// más: así "a:contains(...)" puede matchear un contenedor sin href antes
+// del enlace real sin que la regla devuelva un valor vacío en silencio.
+// El segundo valor de retorno es false si ningún elemento tenía el
+// atributo pedido.
+func valorDeSeleccion(sel *goquery.Selection, atributo string) (string, bool) {
+	if atributo == "" {
+		return strings.TrimSpace(sel.First().Text()), true
+	}
+
+	var valor string
+	var encontrado bool
+	sel.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		v, existe := s.Attr(atributo)
+		if !existe {
+			return true
+		}
+		valor = v
+		encontrado = true
+		return false
+	})
+	return valor, encontrado
+}
+
+// valorDeNodos es el equivalente de valorDeSeleccion para una lista de
+// *html.Node (ruta XPath): devuelve el primer nodo con el atributo
+// pedido, junto con su valor, o el primer nodo con su texto si no se
+// pide atributo.
+func valorDeNodos(nodos []*html.Node, atributo string) (nodo *html.Node, valor string, ok bool) {
+	if atributo == "" {
+		return nodos[0], strings.TrimSpace(htmlquery.InnerText(nodos[0])), true
+	}
+
+	for _, n := range nodos {
+		for _, attr := range n.Attr {
+			if attr.Key == atributo {
+				return n, attr.Val, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+func aplicarPostProcesador(post PostProcesador, valor, urlBase string) (string, error) {
+	switch post {
+	case PostTrim:
+		return strings.TrimSpace(valor), nil
+	case PostResolverRelativa:
+		return resolverURLRelativa(urlBase, valor), nil
+	case PostDebeCoincidir:
+		if valor == "" {
+			return "", fmt.Errorf("post-procesador must-match: valor vacío")
+		}
+		return valor, nil
+	default:
+		return "", fmt.Errorf("post-procesador desconocido: %q", post)
+	}
+}
+
+// resolverURLRelativa convierte una URL relativa en absoluta. Replica la
+// resolución de resolverURLAbsoluta del paquete raíz en lugar de
+// importarlo, para que extract no dependa de servicios_sanitarios.
+func resolverURLRelativa(baseURL, href string) string {
+	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+		return href
+	}
+
+	if strings.HasPrefix(href, "/") {
+		partes := strings.Split(baseURL, "/")
+		if len(partes) >= 3 {
+			return partes[0] + "//" + partes[2] + href
+		}
+	}
+
+	ultimaBarra := strings.LastIndex(baseURL, "/")
+	if ultimaBarra != -1 {
+		return baseURL[:ultimaBarra+1] + href
+	}
+
+	return baseURL + "/" + href
+}
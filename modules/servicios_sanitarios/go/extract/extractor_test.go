@@ -0,0 +1,111 @@
+package extract
+
+import "testing"
+
+const paginaDePrueba = `
+<html><body>
+	<div id="tarifas">
+		<a href="/docs/tarifas-2026.pdf">Tarifas vigentes (PDF)</a>
+		<a href="/docs/otro.html">Otro enlace</a>
+	</div>
+</body></html>
+`
+
+func TestAplicarCadenaCSS(t *testing.T) {
+	reglas := []Rule{
+		{Kind: KindCSS, Expresion: "#tarifas"},
+		{Kind: KindCSS, Expresion: `a[href$=".pdf"]`, Atributo: "href", PostProcesadores: []PostProcesador{PostResolverRelativa}},
+	}
+
+	resultado, err := Aplicar("https://www.example.cl/inicio", []byte(paginaDePrueba), reglas)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if resultado.Valor != "https://www.example.cl/docs/tarifas-2026.pdf" {
+		t.Errorf("valor esperado absoluto, obtenido %q", resultado.Valor)
+	}
+	if resultado.ReglaIndice != 1 {
+		t.Errorf("se esperaba que la última regla aplicada fuera la 1, fue %d", resultado.ReglaIndice)
+	}
+}
+
+func TestAplicarRegexSobreDocumento(t *testing.T) {
+	reglas := []Rule{
+		{Kind: KindRegex, Expresion: `/docs/[\w-]+\.pdf`},
+	}
+
+	resultado, err := Aplicar("https://www.example.cl", []byte(paginaDePrueba), reglas)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if resultado.Valor != "/docs/tarifas-2026.pdf" {
+		t.Errorf("valor esperado /docs/tarifas-2026.pdf, obtenido %q", resultado.Valor)
+	}
+}
+
+func TestAplicarSinCoincidenciasFalla(t *testing.T) {
+	reglas := []Rule{
+		{Kind: KindCSS, Expresion: "#no-existe"},
+	}
+
+	if _, err := Aplicar("https://www.example.cl", []byte(paginaDePrueba), reglas); err == nil {
+		t.Fatal("se esperaba un error por falta de coincidencias")
+	}
+}
+
+func TestAplicarJSONPath(t *testing.T) {
+	documento := `{"tarifas": {"vigente": {"url": "/docs/tarifas-2026.pdf"}}}`
+	reglas := []Rule{
+		{Kind: KindJSONPath, Expresion: "$.tarifas.vigente.url", PostProcesadores: []PostProcesador{PostResolverRelativa}},
+	}
+
+	resultado, err := Aplicar("https://www.example.cl/api", []byte(documento), reglas)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if resultado.Valor != "https://www.example.cl/docs/tarifas-2026.pdf" {
+		t.Errorf("valor esperado absoluto, obtenido %q", resultado.Valor)
+	}
+}
+
+func TestAplicarCSSSaltaElementoSinAtributo(t *testing.T) {
+	pagina := `
+<html><body>
+	<div id="tarifas">
+		<a>Tarifas vigentes</a>
+		<a href="/docs/tarifas-2026.pdf">Tarifas vigentes (PDF)</a>
+	</div>
+</body></html>
+`
+	reglas := []Rule{
+		{Kind: KindCSS, Expresion: `a:contains("Tarifas vigentes")`, Atributo: "href"},
+	}
+
+	resultado, err := Aplicar("https://www.example.cl", []byte(pagina), reglas)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if resultado.Valor != "/docs/tarifas-2026.pdf" {
+		t.Errorf("valor esperado /docs/tarifas-2026.pdf, obtenido %q", resultado.Valor)
+	}
+}
+
+func TestAplicarCSSFallaSiNingunElementoTieneAtributo(t *testing.T) {
+	reglas := []Rule{
+		{Kind: KindCSS, Expresion: "#tarifas a", Atributo: "data-inexistente"},
+	}
+
+	if _, err := Aplicar("https://www.example.cl", []byte(paginaDePrueba), reglas); err == nil {
+		t.Fatal("se esperaba un error porque ningún elemento tiene el atributo pedido")
+	}
+}
+
+func TestAplicarMustMatchFallaConValorVacio(t *testing.T) {
+	reglas := []Rule{
+		{Kind: KindRegex, Expresion: `no-deberia-coincidir-\d+`},
+	}
+
+	if _, err := Aplicar("https://www.example.cl", []byte(paginaDePrueba), reglas); err == nil {
+		t.Fatal("se esperaba un error por falta de coincidencias")
+	}
+}
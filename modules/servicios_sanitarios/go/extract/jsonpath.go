@@ -0,0 +1,69 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// aplicarJSONPath interpreta un subconjunto de JSONPath sin filtros ni
+// comodines ("$.campo.subcampo[0].otro") y lo resuelve contra datos, un
+// documento JSON en texto plano. Es deliberadamente pequeño: alcanza
+// para navegar la respuesta de un regulador que expone sus datos como
+// JSON en vez de HTML, sin sumar una dependencia externa para el resto.
+func aplicarJSONPath(datos, expresion string) (string, error) {
+	var valor interface{}
+	if err := json.Unmarshal([]byte(datos), &valor); err != nil {
+		return "", fmt.Errorf("documento no es JSON válido: %w", err)
+	}
+
+	segmentos, err := segmentosJSONPath(expresion)
+	if err != nil {
+		return "", err
+	}
+
+	for _, segmento := range segmentos {
+		switch v := valor.(type) {
+		case map[string]interface{}:
+			siguiente, ok := v[segmento]
+			if !ok {
+				return "", fmt.Errorf("campo no encontrado: %s", segmento)
+			}
+			valor = siguiente
+		case []interface{}:
+			indice, err := strconv.Atoi(segmento)
+			if err != nil || indice < 0 || indice >= len(v) {
+				return "", fmt.Errorf("índice inválido: %s", segmento)
+			}
+			valor = v[indice]
+		default:
+			return "", fmt.Errorf("no se puede navegar más allá de %q", segmento)
+		}
+	}
+
+	if texto, ok := valor.(string); ok {
+		return texto, nil
+	}
+	return fmt.Sprintf("%v", valor), nil
+}
+
+// segmentosJSONPath parte una expresión "$.a.b[0].c" (el "$." inicial es
+// opcional) en sus segmentos ["a", "b", "0", "c"].
+func segmentosJSONPath(expresion string) ([]string, error) {
+	expresion = strings.TrimPrefix(expresion, "$")
+	expresion = strings.TrimPrefix(expresion, ".")
+	expresion = strings.ReplaceAll(expresion, "[", ".")
+	expresion = strings.ReplaceAll(expresion, "]", "")
+
+	var segmentos []string
+	for _, s := range strings.Split(expresion, ".") {
+		if s != "" {
+			segmentos = append(segmentos, s)
+		}
+	}
+	if len(segmentos) == 0 {
+		return nil, fmt.Errorf("expresión JSONPath vacía")
+	}
+	return segmentos, nil
+}
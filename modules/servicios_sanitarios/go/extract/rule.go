@@ -0,0 +1,62 @@
+// Package extract implementa un pipeline de extracción HTML/JSON guiado
+// por reglas declarativas (Rule), para ubicar un dato de interés en la
+// página de un regulador sin escribir código Go específico para su
+// markup. Una cadena de reglas puede ir reduciendo el alcance, p.ej.
+// "ubicar la sección de Tarifas" seguida de "dentro de ella, el primer
+// enlace a PDF".
+package extract
+
+// Kind identifica el tipo de una Rule.
+type Kind string
+
+const (
+	// KindCSS busca con un selector CSS (sintaxis de goquery/cascadia).
+	KindCSS Kind = "css"
+	// KindXPath busca con una expresión XPath 1.0.
+	KindXPath Kind = "xpath"
+	// KindRegex busca con una expresión regular (sintaxis RE2 de Go).
+	KindRegex Kind = "regex"
+	// KindJSONPath navega un documento JSON. Soporta un subconjunto de
+	// JSONPath sin filtros ni comodines: "$.campo.subcampo[0].otro".
+	KindJSONPath Kind = "jsonpath"
+)
+
+// PostProcesador identifica una transformación a aplicar sobre el valor
+// que produjo una Rule, antes de pasárselo a la siguiente o de devolverlo
+// como resultado final.
+type PostProcesador string
+
+const (
+	// PostTrim recorta espacios en blanco al inicio y el final.
+	PostTrim PostProcesador = "trim"
+	// PostResolverRelativa convierte una URL relativa en absoluta,
+	// resolviéndola contra la URL del target.
+	PostResolverRelativa PostProcesador = "resolve-relative"
+	// PostDebeCoincidir falla la extracción si el valor queda vacío,
+	// en lugar de dejar que la regla siguiente (o el llamador) reciba
+	// una cadena vacía silenciosamente.
+	PostDebeCoincidir PostProcesador = "must-match"
+)
+
+// Rule describe un paso de una cadena de extracción: qué tipo de regla
+// aplicar, su expresión, el atributo a leer (css/xpath; vacío = texto
+// del nodo) y los post-procesadores a aplicarle al valor resultante.
+type Rule struct {
+	Kind             Kind             `yaml:"kind" json:"kind"`
+	Expresion        string           `yaml:"expresion" json:"expresion"`
+	Atributo         string           `yaml:"atributo,omitempty" json:"atributo,omitempty"`
+	PostProcesadores []PostProcesador `yaml:"post_procesadores,omitempty" json:"post_procesadores,omitempty"`
+}
+
+// Resultado es el resultado de aplicar una cadena de Rule: qué regla (por
+// posición) produjo el valor final, el texto crudo que coincidió antes de
+// post-procesarlo y el valor final. Se guarda junto al resultado de una
+// verificación para que el llamador pueda auditar por qué se extrajo ese
+// valor.
+type Resultado struct {
+	ReglaIndice int    `json:"regla_indice"`
+	ReglaKind   Kind   `json:"regla_kind"`
+	Expresion   string `json:"expresion"`
+	TextoCrudo  string `json:"texto_crudo"`
+	Valor       string `json:"valor"`
+}
@@ -0,0 +1,263 @@
+package servicios_sanitarios
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// selectorTarifasPorDefecto es el selector CSS usado históricamente por
+// VerificarSISS para ubicar el enlace de "Tarifas vigentes" en la home de
+// SISS, expresado como selector en lugar de texto de enlace.
+const selectorTarifasPorDefecto = `a:contains("Tarifas vigentes")`
+
+// Extractor extrae información de páginas HTML a partir de selectores CSS,
+// reemplazando al recorrido manual de *html.Node de ExtraerURLPorTexto.
+type Extractor struct{}
+
+// NewExtractor crea un Extractor listo para usar.
+func NewExtractor() *Extractor {
+	return &Extractor{}
+}
+
+// obtenerDocumento descarga url y lo parsea como documento goquery, honrando
+// la cancelación de ctx durante la solicitud HTTP y el parseo del HTML.
+func (e *Extractor) obtenerDocumento(ctx context.Context, url string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al construir la solicitud: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener página: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado HTTP: %d", resp.StatusCode)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error al parsear HTML: %w", err)
+	}
+
+	return doc, nil
+}
+
+// ExtraerPorSelector devuelve las URLs (resueltas a absolutas) de todos los
+// elementos que matchean selector y tienen atributo href.
+//
+// Deprecated: usa ExtraerPorSelectorContext con un context.WithTimeout, que
+// permite cancelar la operación desde el llamador.
+func (e *Extractor) ExtraerPorSelector(url, selector string, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return e.ExtraerPorSelectorContext(ctx, url, selector)
+}
+
+// ExtraerPorSelectorContext es la variante de ExtraerPorSelector que acepta
+// un context.Context para cancelación, en lugar de un timeout fijo.
+func (e *Extractor) ExtraerPorSelectorContext(ctx context.Context, url, selector string) ([]string, error) {
+	doc, err := e.obtenerDocumento(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultados []string
+	doc.Find(selector).Each(func(_ int, sel *goquery.Selection) {
+		href, existe := sel.Attr("href")
+		if !existe {
+			return
+		}
+		resultados = append(resultados, resolverURLAbsoluta(url, href))
+	})
+
+	if len(resultados) == 0 {
+		return nil, fmt.Errorf("no se encontraron elementos para el selector: %s", selector)
+	}
+
+	return resultados, nil
+}
+
+// obtenerCuerpo descarga url y devuelve su cuerpo como texto, honrando la
+// cancelación de ctx durante la solicitud HTTP.
+func (e *Extractor) obtenerCuerpo(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error al construir la solicitud: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener página: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("código de estado HTTP: %d", resp.StatusCode)
+	}
+
+	cuerpo, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error al leer cuerpo de la respuesta: %w", err)
+	}
+
+	return string(cuerpo), nil
+}
+
+// ExtraerPorRegexContext descarga url y devuelve todas las coincidencias
+// del patrón de expresión regular patron en su cuerpo, como alternativa a
+// ExtraerPorSelectorContext para sitios donde un selector CSS no alcanza.
+func (e *Extractor) ExtraerPorRegexContext(ctx context.Context, url, patron string) ([]string, error) {
+	cuerpo, err := e.obtenerCuerpo(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(patron)
+	if err != nil {
+		return nil, fmt.Errorf("expresión regular inválida: %w", err)
+	}
+
+	coincidencias := re.FindAllString(cuerpo, -1)
+	if len(coincidencias) == 0 {
+		return nil, fmt.Errorf("no se encontraron coincidencias para el patrón: %s", patron)
+	}
+
+	return coincidencias, nil
+}
+
+// ExtraerTextoPorSelector devuelve el texto (recortado) de todos los
+// elementos que matchean selector.
+func (e *Extractor) ExtraerTextoPorSelector(url, selector string, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	doc, err := e.obtenerDocumento(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultados []string
+	doc.Find(selector).Each(func(_ int, sel *goquery.Selection) {
+		resultados = append(resultados, strings.TrimSpace(sel.Text()))
+	})
+
+	if len(resultados) == 0 {
+		return nil, fmt.Errorf("no se encontraron elementos para el selector: %s", selector)
+	}
+
+	return resultados, nil
+}
+
+// ExtraerTabla convierte la primera tabla que matchee selector en una lista
+// de filas, usando la primera fila (th, o td si no hay th) como encabezados.
+func (e *Extractor) ExtraerTabla(url, selector string, timeout time.Duration) ([]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	doc, err := e.obtenerDocumento(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	tabla := doc.Find(selector).First()
+	if tabla.Length() == 0 {
+		return nil, fmt.Errorf("no se encontró tabla para el selector: %s", selector)
+	}
+
+	var encabezados []string
+	filas := tabla.Find("tr")
+
+	var resultado []map[string]string
+	filas.Each(func(i int, fila *goquery.Selection) {
+		celdas := fila.Find("th, td")
+		if i == 0 && fila.Find("th").Length() > 0 {
+			celdas.Each(func(_ int, celda *goquery.Selection) {
+				encabezados = append(encabezados, strings.TrimSpace(celda.Text()))
+			})
+			return
+		}
+
+		if encabezados == nil {
+			celdas.Each(func(j int, _ *goquery.Selection) {
+				encabezados = append(encabezados, fmt.Sprintf("columna_%d", j+1))
+			})
+		}
+
+		fila2 := make(map[string]string)
+		celdas.Each(func(j int, celda *goquery.Selection) {
+			if j >= len(encabezados) {
+				return
+			}
+			fila2[encabezados[j]] = strings.TrimSpace(celda.Text())
+		})
+		if len(fila2) > 0 {
+			resultado = append(resultado, fila2)
+		}
+	})
+
+	return resultado, nil
+}
+
+// ExtraerURLPorTexto extrae la URL de un enlace en una página HTML buscando
+// por el texto del enlace. Se mantiene por compatibilidad hacia atrás,
+// implementado como un caso particular de ExtraerPorSelector con el
+// pseudo-selector :contains.
+//
+// Deprecated: usa ExtraerURLPorTextoContext con un context.WithTimeout, que
+// permite cancelar la operación desde el llamador.
+func ExtraerURLPorTexto(url, textoBuscar string, timeout int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return ExtraerURLPorTextoContext(ctx, url, textoBuscar)
+}
+
+// ExtraerURLPorTextoContext es la variante de ExtraerURLPorTexto que acepta
+// un context.Context para cancelación, en lugar de un timeout fijo.
+//
+// Recorre los enlaces a mano en lugar de usar el pseudo-selector
+// a:contains(), que en cascadia compara de forma sensible a mayúsculas y
+// minúsculas: textoBuscar se busca sin distinguir mayúsculas, igual que el
+// recorrido manual de *html.Node que reemplazó.
+func ExtraerURLPorTextoContext(ctx context.Context, url, textoBuscar string) (string, error) {
+	doc, err := NewExtractor().obtenerDocumento(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	textoBuscarMin := strings.ToLower(textoBuscar)
+
+	var resultado string
+	doc.Find("a").EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+		if !strings.Contains(strings.ToLower(sel.Text()), textoBuscarMin) {
+			return true
+		}
+		href, existe := sel.Attr("href")
+		if !existe {
+			return true
+		}
+		resultado = resolverURLAbsoluta(url, href)
+		return false
+	})
+
+	if resultado == "" {
+		return "", fmt.Errorf("no se encontró enlace con el texto: %s", textoBuscar)
+	}
+
+	return resultado, nil
+}
@@ -0,0 +1,107 @@
+package servicios_sanitarios
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const paginaExtractorPrueba = `<html><body>
+<ul>
+	<li class="tarifa"><a href="/tarifas-v1.html">Tarifas Vigentes</a></li>
+	<li class="tarifa"><a href="/tarifas-v2.html">Otro enlace</a></li>
+</ul>
+</body></html>`
+
+func servidorExtractorPrueba() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(paginaExtractorPrueba))
+	}))
+}
+
+func TestExtraerPorSelectorContext(t *testing.T) {
+	servidor := servidorExtractorPrueba()
+	defer servidor.Close()
+
+	extractor := NewExtractor()
+
+	casos := []struct {
+		nombre       string
+		selector     string
+		esperaError  bool
+		cantResuelto int
+	}{
+		{nombre: "un match", selector: `li.tarifa:first-child a`, cantResuelto: 1},
+		{nombre: "múltiples matches", selector: "li.tarifa a", cantResuelto: 2},
+		{nombre: "sin matches", selector: "li.inexistente a", esperaError: true},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			resultados, err := extractor.ExtraerPorSelectorContext(context.Background(), servidor.URL, caso.selector)
+			if caso.esperaError {
+				if err == nil {
+					t.Fatalf("se esperaba error para el selector %q", caso.selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if len(resultados) != caso.cantResuelto {
+				t.Errorf("se esperaban %d resultados, hay %d: %v", caso.cantResuelto, len(resultados), resultados)
+			}
+		})
+	}
+}
+
+func TestExtraerURLPorTextoContext(t *testing.T) {
+	servidor := servidorExtractorPrueba()
+	defer servidor.Close()
+
+	casos := []struct {
+		nombre      string
+		texto       string
+		esperaError bool
+		esperaURL   string
+	}{
+		{nombre: "coincidencia exacta", texto: "Tarifas Vigentes", esperaURL: servidor.URL + "/tarifas-v1.html"},
+		{nombre: "coincidencia insensible a mayúsculas", texto: "tarifas vigentes", esperaURL: servidor.URL + "/tarifas-v1.html"},
+		{nombre: "sin coincidencia", texto: "no existe", esperaError: true},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			url, err := ExtraerURLPorTextoContext(context.Background(), servidor.URL, caso.texto)
+			if caso.esperaError {
+				if err == nil {
+					t.Fatalf("se esperaba error para el texto %q", caso.texto)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if url != caso.esperaURL {
+				t.Errorf("URL esperada %q, obtenida %q", caso.esperaURL, url)
+			}
+		})
+	}
+}
+
+func TestExtraerURLPorTextoRespetaTimeout(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(paginaExtractorPrueba))
+	}))
+	defer servidor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if _, err := ExtraerURLPorTextoContext(ctx, servidor.URL, "Tarifas Vigentes"); err == nil {
+		t.Fatal("se esperaba error por cancelación del context")
+	}
+}
@@ -0,0 +1,157 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+// etagTarea calcula un ETag fuerte para tarea a partir de su ID y su
+// última modificación (FechaCompletado si está completada, si no
+// FechaCreacion), para que los clientes CalDAV puedan validar su caché
+// local sin tener que volver a descargar el VTODO completo.
+//
+// Estas rutas son de solo lectura (no hay PUT/DELETE), así que el ETag
+// solo sirve para validación de caché vía If-None-Match, no como control
+// de concurrencia optimista sobre escrituras.
+func etagTarea(tarea ss.Tarea) string {
+	ultimaModificacion := tarea.FechaCreacion
+	if tarea.FechaCompletado != nil {
+		ultimaModificacion = *tarea.FechaCompletado
+	}
+	return fmt.Sprintf(`"%s-%d"`, tarea.ID, ultimaModificacion.UTC().Unix())
+}
+
+// handleCalDAVPropfind atiende PROPFIND /caldav/tareas, listando cada
+// tarea como un recurso VTODO con su ETag (RFC 4918, RFC 4791).
+//
+// @Summary Propiedades WebDAV de la colección de tareas
+// @Router /caldav/tareas [propfind]
+func (s *Server) handleCalDAVPropfind(w http.ResponseWriter, r *http.Request) {
+	tareas := s.servicio.ListarTareas("", "")
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	sb.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">` + "\n")
+	for _, tarea := range tareas {
+		fmt.Fprintf(&sb, "<D:response><D:href>/caldav/tareas/%s.ics</D:href>", tarea.ID)
+		sb.WriteString("<D:propstat><D:prop>")
+		fmt.Fprintf(&sb, "<D:getetag>%s</D:getetag>", etagTarea(tarea))
+		sb.WriteString(`<D:getcontenttype>text/calendar; component=vtodo</D:getcontenttype>`)
+		sb.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>\n")
+	}
+	sb.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	w.Write([]byte(sb.String()))
+}
+
+// handleCalDAVReport atiende REPORT /caldav/tareas (calendar-query o
+// calendar-multiget), devolviendo el VTODO completo de cada tarea junto a
+// su ETag, tal como lo esperan los clientes CalDAV al sincronizar.
+//
+// @Summary Datos de calendario de la colección de tareas
+// @Router /caldav/tareas [report]
+func (s *Server) handleCalDAVReport(w http.ResponseWriter, r *http.Request) {
+	tareas := s.servicio.ListarTareas("", "")
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	sb.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">` + "\n")
+	for _, tarea := range tareas {
+		var vtodo strings.Builder
+		if err := ss.EscribirVTODO(&vtodo, tarea); err != nil {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "<D:response><D:href>/caldav/tareas/%s.ics</D:href>", tarea.ID)
+		sb.WriteString("<D:propstat><D:prop>")
+		fmt.Fprintf(&sb, "<D:getetag>%s</D:getetag>", etagTarea(tarea))
+		fmt.Fprintf(&sb, "<C:calendar-data>%s</C:calendar-data>", escaparXML(vtodo.String()))
+		sb.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>\n")
+	}
+	sb.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	w.Write([]byte(sb.String()))
+}
+
+// handleCalDAVTarea atiende GET /caldav/tareas/{id}.ics, devolviendo el
+// VTODO de una única tarea con su ETag, para clientes que descargan
+// recursos individuales en vez de usar REPORT. Si el cliente manda
+// If-None-Match y el ETag coincide con el actual, responde 304 Not
+// Modified sin cuerpo, ahorrándole la descarga del VTODO completo.
+//
+// @Summary VTODO de una tarea
+// @Param id path string true "ID de la tarea"
+// @Success 200 {string} string "text/calendar"
+// @Success 304 {string} string "no modificado, ETag sin cambios"
+// @Failure 404 {object} errorEnvelope
+// @Router /caldav/tareas/{id}.ics [get]
+func (s *Server) handleCalDAVTarea(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	tarea, ok, err := s.servicio.ObtenerTarea(id)
+	if err != nil {
+		responderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		responderError(w, http.StatusNotFound, "tarea no encontrada: "+id)
+		return
+	}
+
+	etag := etagTarea(tarea)
+
+	if coincideETag(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var vtodo strings.Builder
+	if err := ss.EscribirVTODO(&vtodo, tarea); err != nil {
+		responderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", etag)
+	w.Write([]byte(vtodo.String()))
+}
+
+// coincideETag indica si etag aparece entre los valores separados por
+// comas de la cabecera If-None-Match, o si esta es "*" (cualquier
+// recurso existente).
+func coincideETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	for _, valor := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimSpace(valor) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// escaparXML escapa el contenido de un elemento de texto XML (usado para
+// incrustar calendar-data, que puede traer los caracteres especiales que
+// RFC 5545 escapa de otra forma, dentro de un documento XML).
+func escaparXML(texto string) string {
+	r := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return r.Replace(texto)
+}
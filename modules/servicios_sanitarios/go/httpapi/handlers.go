@@ -0,0 +1,197 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+// errorEnvelope sigue la misma forma que los Resultado* del módulo
+// (Exito/Error) para que los clientes puedan tratar los errores de la API
+// igual que los resultados de operaciones como VerificarSISS.
+type errorEnvelope struct {
+	Exito bool   `json:"exito"`
+	Error string `json:"error"`
+}
+
+func responderJSON(w http.ResponseWriter, codigo int, cuerpo interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(codigo)
+	json.NewEncoder(w).Encode(cuerpo)
+}
+
+func responderError(w http.ResponseWriter, codigo int, mensaje string) {
+	responderJSON(w, codigo, errorEnvelope{Exito: false, Error: mensaje})
+}
+
+// handleListarTareas atiende GET /tareas?estado=&prioridad=
+//
+// @Summary Lista tareas
+// @Param estado query string false "Filtrar por estado (pendiente|completado)"
+// @Param prioridad query string false "Filtrar por prioridad (baja|media|alta|critica)"
+// @Success 200 {array} ss.Tarea
+// @Router /tareas [get]
+func (s *Server) handleListarTareas(w http.ResponseWriter, r *http.Request) {
+	estado := r.URL.Query().Get("estado")
+	prioridad := r.URL.Query().Get("prioridad")
+
+	tareas := s.servicio.ListarTareas(estado, prioridad)
+	responderJSON(w, http.StatusOK, tareas)
+}
+
+type agregarTareaRequest struct {
+	Descripcion string                 `json:"descripcion"`
+	Prioridad   string                 `json:"prioridad"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+// handleAgregarTarea atiende POST /tareas
+//
+// @Summary Crea una tarea
+// @Accept json
+// @Param tarea body agregarTareaRequest true "Datos de la tarea"
+// @Success 201 {object} ss.Tarea
+// @Failure 400 {object} errorEnvelope
+// @Router /tareas [post]
+func (s *Server) handleAgregarTarea(w http.ResponseWriter, r *http.Request) {
+	var cuerpo agregarTareaRequest
+	if err := json.NewDecoder(r.Body).Decode(&cuerpo); err != nil {
+		responderError(w, http.StatusBadRequest, "cuerpo JSON inválido")
+		return
+	}
+
+	tarea, err := s.servicio.AgregarTarea(cuerpo.Descripcion, cuerpo.Prioridad, cuerpo.Metadata)
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	responderJSON(w, http.StatusCreated, tarea)
+}
+
+// handleCompletarTarea atiende POST /tareas/{id}/completar
+//
+// @Summary Marca una tarea como completada
+// @Param id path string true "ID de la tarea"
+// @Success 200 {object} ss.Tarea
+// @Failure 404 {object} errorEnvelope
+// @Router /tareas/{id}/completar [post]
+func (s *Server) handleCompletarTarea(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if !s.servicio.CompletarTarea(id) {
+		responderError(w, http.StatusNotFound, "tarea no encontrada: "+id)
+		return
+	}
+
+	responderJSON(w, http.StatusOK, map[string]bool{"exito": true})
+}
+
+// handleEstadisticas atiende GET /estadisticas
+//
+// @Summary Estadísticas del módulo
+// @Success 200 {object} ss.Estadisticas
+// @Router /estadisticas [get]
+func (s *Server) handleEstadisticas(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, s.servicio.ObtenerEstadisticas())
+}
+
+// handleInfo atiende GET /info
+//
+// @Summary Información general del módulo
+// @Success 200 {object} ss.Info
+// @Router /info [get]
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, s.servicio.ObtenerInfo())
+}
+
+// handleVerificarSISS atiende POST /verificar/siss
+//
+// @Summary Dispara una verificación de la URL de SISS
+// @Success 200 {object} ss.ResultadoVerificacionSISS
+// @Router /verificar/siss [post]
+func (s *Server) handleVerificarSISS(w http.ResponseWriter, r *http.Request) {
+	resultado := s.servicio.VerificarSISSContext(r.Context(), "")
+	codigo := http.StatusOK
+	if !resultado.Exito {
+		codigo = http.StatusBadGateway
+	}
+	responderJSON(w, codigo, resultado)
+}
+
+// obtenerVerificacionOResponderError busca la última verificación de
+// target y, si falla o no existe, ya escribe la respuesta de error
+// correspondiente. ok es false en ese caso, señal para que el handler no
+// continúe.
+func (s *Server) obtenerVerificacionOResponderError(w http.ResponseWriter, r *http.Request, target string) (datos ss.DatosVerificacionSISS, ok bool) {
+	datos, encontrado, err := s.servicio.ObtenerVerificacion(r.Context(), target)
+	if err != nil {
+		responderError(w, http.StatusInternalServerError, err.Error())
+		return ss.DatosVerificacionSISS{}, false
+	}
+	if !encontrado {
+		responderError(w, http.StatusNotFound, "sin verificaciones guardadas para target: "+target)
+		return ss.DatosVerificacionSISS{}, false
+	}
+
+	return datos, true
+}
+
+// handleSISSLatest atiende GET /siss/{target}/latest
+//
+// @Summary Último resultado de verificación de un target
+// @Param target path string true "Nombre del target (o ruta de su archivo JSON)"
+// @Success 200 {object} ss.DatosVerificacionSISS
+// @Failure 404 {object} errorEnvelope
+// @Router /siss/{target}/latest [get]
+func (s *Server) handleSISSLatest(w http.ResponseWriter, r *http.Request) {
+	target := mux.Vars(r)["target"]
+
+	datos, ok := s.obtenerVerificacionOResponderError(w, r, target)
+	if !ok {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, datos)
+}
+
+// handleSISSHistorial atiende GET /siss/{target}/historial
+//
+// @Summary Historial de cambios de un target
+// @Param target path string true "Nombre del target (o ruta de su archivo JSON)"
+// @Success 200 {array} ss.EntradaHistorial
+// @Failure 404 {object} errorEnvelope
+// @Router /siss/{target}/historial [get]
+func (s *Server) handleSISSHistorial(w http.ResponseWriter, r *http.Request) {
+	target := mux.Vars(r)["target"]
+
+	datos, ok := s.obtenerVerificacionOResponderError(w, r, target)
+	if !ok {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, datos.Historial)
+}
+
+// handleActivar atiende POST /activar
+//
+// @Summary Activa el módulo
+// @Success 200 {object} ss.Info
+// @Router /activar [post]
+func (s *Server) handleActivar(w http.ResponseWriter, r *http.Request) {
+	s.servicio.Activar()
+	responderJSON(w, http.StatusOK, s.servicio.ObtenerInfo())
+}
+
+// handleDesactivar atiende POST /desactivar
+//
+// @Summary Desactiva el módulo
+// @Success 200 {object} ss.Info
+// @Router /desactivar [post]
+func (s *Server) handleDesactivar(w http.ResponseWriter, r *http.Request) {
+	s.servicio.Desactivar()
+	responderJSON(w, http.StatusOK, s.servicio.ObtenerInfo())
+}
@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// middlewareRequestID asigna un ID único a cada solicitud, lo expone en la
+// cabecera X-Request-ID y lo deja disponible en el contexto para logging.
+func middlewareRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		w.Header().Set("X-Request-ID", requestID)
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// middlewareLogging registra método, ruta, código de estado y duración de
+// cada solicitud, identificada por su request ID.
+func (s *Server) middlewareLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inicio := time.Now()
+		envoltorio := &responseWriterConEstado{ResponseWriter: w, codigoEstado: http.StatusOK}
+
+		next.ServeHTTP(envoltorio, r)
+
+		requestID, _ := r.Context().Value(requestIDKey).(string)
+		s.logger.Printf("request_id=%s metodo=%s ruta=%s estado=%d duracion=%s",
+			requestID, r.Method, r.URL.Path, envoltorio.codigoEstado, time.Since(inicio))
+	})
+}
+
+// responseWriterConEstado envuelve http.ResponseWriter para capturar el
+// código de estado efectivamente escrito por el handler.
+type responseWriterConEstado struct {
+	http.ResponseWriter
+	codigoEstado int
+}
+
+func (w *responseWriterConEstado) WriteHeader(codigo int) {
+	w.codigoEstado = codigo
+	w.ResponseWriter.WriteHeader(codigo)
+}
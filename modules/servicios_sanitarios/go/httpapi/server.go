@@ -0,0 +1,93 @@
+// Package httpapi expone ServiciosSanitarios como una API REST en JSON,
+// para que el módulo pueda adoptarse desde front-ends web y stacks de
+// cron/monitoreo sin requerir integración en proceso.
+package httpapi
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+// Server mantiene el router HTTP montado sobre un ServiciosSanitarios.
+type Server struct {
+	servicio   *ss.ServiciosSanitarios
+	router     *mux.Router
+	httpServer *http.Server
+	logger     *log.Logger
+}
+
+// NewServer crea un Server listo para servir las rutas de la API sobre el
+// servicio recibido. Si logger es nil se usa log.Default().
+func NewServer(servicio *ss.ServiciosSanitarios, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.New(os.Stderr, "httpapi: ", log.LstdFlags)
+	}
+
+	s := &Server{
+		servicio: servicio,
+		logger:   logger,
+	}
+	s.router = s.construirRouter()
+
+	return s
+}
+
+// Handler devuelve el http.Handler del servidor, útil para montarlo dentro
+// de otro router o para pruebas con httptest.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+func (s *Server) construirRouter() *mux.Router {
+	r := mux.NewRouter()
+	r.Use(middlewareRequestID)
+	r.Use(s.middlewareLogging)
+
+	r.HandleFunc("/tareas", s.handleListarTareas).Methods(http.MethodGet)
+	r.HandleFunc("/tareas", s.handleAgregarTarea).Methods(http.MethodPost)
+	r.HandleFunc("/tareas/{id}/completar", s.handleCompletarTarea).Methods(http.MethodPost)
+	r.HandleFunc("/tareas/{id}/complete", s.handleCompletarTarea).Methods(http.MethodPatch)
+	r.HandleFunc("/estadisticas", s.handleEstadisticas).Methods(http.MethodGet)
+	r.HandleFunc("/stats", s.handleEstadisticas).Methods(http.MethodGet)
+	r.HandleFunc("/info", s.handleInfo).Methods(http.MethodGet)
+	r.HandleFunc("/verificar/siss", s.handleVerificarSISS).Methods(http.MethodPost)
+	r.HandleFunc("/siss/{target}/latest", s.handleSISSLatest).Methods(http.MethodGet)
+	r.HandleFunc("/siss/{target}/historial", s.handleSISSHistorial).Methods(http.MethodGet)
+	r.HandleFunc("/activar", s.handleActivar).Methods(http.MethodPost)
+	r.HandleFunc("/desactivar", s.handleDesactivar).Methods(http.MethodPost)
+	r.HandleFunc("/caldav/tareas", s.handleCalDAVPropfind).Methods("PROPFIND")
+	r.HandleFunc("/caldav/tareas", s.handleCalDAVReport).Methods("REPORT")
+	r.HandleFunc("/caldav/tareas/{id}.ics", s.handleCalDAVTarea).Methods(http.MethodGet)
+
+	return r
+}
+
+// ListenAndServe arranca el servidor HTTP en addr. Bloquea hasta que el
+// servidor se detiene (por error o por Shutdown).
+func (s *Server) ListenAndServe(addr string) error {
+	s.httpServer = &http.Server{
+		Addr:    addr,
+		Handler: s.router,
+	}
+
+	err := s.httpServer.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown apaga el servidor de forma ordenada, esperando a que las
+// solicitudes en curso terminen o a que ctx expire.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
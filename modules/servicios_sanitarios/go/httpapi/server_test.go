@@ -0,0 +1,188 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+func nuevoServidorDePrueba(t *testing.T) (*httptest.Server, *ss.ServiciosSanitarios) {
+	t.Helper()
+
+	servicio := ss.NewServiciosSanitarios("API Test")
+	servidor := NewServer(servicio, nil)
+
+	return httptest.NewServer(servidor.Handler()), servicio
+}
+
+func TestHandleAgregarYListarTareas(t *testing.T) {
+	ts, _ := nuevoServidorDePrueba(t)
+	defer ts.Close()
+
+	cuerpo, _ := json.Marshal(map[string]string{
+		"descripcion": "Revisar estanque",
+		"prioridad":   "alta",
+	})
+
+	resp, err := http.Post(ts.URL+"/tareas", "application/json", bytes.NewReader(cuerpo))
+	if err != nil {
+		t.Fatalf("Error al crear tarea: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Status esperado 201, obtenido %d", resp.StatusCode)
+	}
+
+	var tarea ss.Tarea
+	if err := json.NewDecoder(resp.Body).Decode(&tarea); err != nil {
+		t.Fatalf("Error al decodificar respuesta: %v", err)
+	}
+	if tarea.Descripcion != "Revisar estanque" {
+		t.Errorf("Descripción esperada 'Revisar estanque', obtenida '%s'", tarea.Descripcion)
+	}
+
+	respLista, err := http.Get(ts.URL + "/tareas")
+	if err != nil {
+		t.Fatalf("Error al listar tareas: %v", err)
+	}
+	defer respLista.Body.Close()
+
+	var tareas []ss.Tarea
+	if err := json.NewDecoder(respLista.Body).Decode(&tareas); err != nil {
+		t.Fatalf("Error al decodificar lista: %v", err)
+	}
+	if len(tareas) != 1 {
+		t.Errorf("Se esperaba 1 tarea, se obtuvieron %d", len(tareas))
+	}
+}
+
+func TestHandleAgregarTareaPrioridadInvalida(t *testing.T) {
+	ts, _ := nuevoServidorDePrueba(t)
+	defer ts.Close()
+
+	cuerpo, _ := json.Marshal(map[string]string{
+		"descripcion": "Tarea",
+		"prioridad":   "urgentisima",
+	})
+
+	resp, err := http.Post(ts.URL+"/tareas", "application/json", bytes.NewReader(cuerpo))
+	if err != nil {
+		t.Fatalf("Error al crear tarea: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status esperado 400, obtenido %d", resp.StatusCode)
+	}
+}
+
+func TestHandleCompletarTareaInexistente(t *testing.T) {
+	ts, _ := nuevoServidorDePrueba(t)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/tareas/no-existe/completar", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Error en la solicitud: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Status esperado 404, obtenido %d", resp.StatusCode)
+	}
+}
+
+func TestHandleEstadisticasEInfo(t *testing.T) {
+	ts, servicio := nuevoServidorDePrueba(t)
+	defer ts.Close()
+	servicio.AgregarTarea("Tarea 1", "baja", nil)
+
+	resp, err := http.Get(ts.URL + "/estadisticas")
+	if err != nil {
+		t.Fatalf("Error al obtener estadísticas: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var stats ss.Estadisticas
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("Error al decodificar estadísticas: %v", err)
+	}
+	if stats.Total != 1 {
+		t.Errorf("Total esperado 1, obtenido %d", stats.Total)
+	}
+
+	respInfo, err := http.Get(ts.URL + "/info")
+	if err != nil {
+		t.Fatalf("Error al obtener info: %v", err)
+	}
+	defer respInfo.Body.Close()
+	if respInfo.StatusCode != http.StatusOK {
+		t.Errorf("Status esperado 200, obtenido %d", respInfo.StatusCode)
+	}
+}
+
+func TestHandleCalDAV(t *testing.T) {
+	ts, servicio := nuevoServidorDePrueba(t)
+	defer ts.Close()
+	tarea, err := servicio.AgregarTarea("Revisar estanque", "alta", nil)
+	if err != nil {
+		t.Fatalf("Error al crear tarea: %v", err)
+	}
+
+	req, _ := http.NewRequest("PROPFIND", ts.URL+"/caldav/tareas", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error en PROPFIND: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		t.Fatalf("Status esperado 207, obtenido %d", resp.StatusCode)
+	}
+
+	respTarea, err := http.Get(ts.URL + "/caldav/tareas/" + tarea.ID + ".ics")
+	if err != nil {
+		t.Fatalf("Error al obtener VTODO: %v", err)
+	}
+	defer respTarea.Body.Close()
+	if respTarea.StatusCode != http.StatusOK {
+		t.Fatalf("Status esperado 200, obtenido %d", respTarea.StatusCode)
+	}
+	if respTarea.Header.Get("ETag") == "" {
+		t.Error("se esperaba un ETag en la respuesta")
+	}
+
+	etag := respTarea.Header.Get("ETag")
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/caldav/tareas/"+tarea.ID+".ics", nil)
+	req.Header.Set("If-None-Match", etag)
+	respCondicional, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error en GET condicional: %v", err)
+	}
+	defer respCondicional.Body.Close()
+	if respCondicional.StatusCode != http.StatusNotModified {
+		t.Errorf("Status esperado 304, obtenido %d", respCondicional.StatusCode)
+	}
+}
+
+func TestHandleActivarDesactivar(t *testing.T) {
+	ts, servicio := nuevoServidorDePrueba(t)
+	defer ts.Close()
+
+	if _, err := http.Post(ts.URL+"/desactivar", "application/json", nil); err != nil {
+		t.Fatalf("Error al desactivar: %v", err)
+	}
+	if servicio.EstaActivo() {
+		t.Error("El módulo debe quedar desactivado")
+	}
+
+	if _, err := http.Post(ts.URL+"/activar", "application/json", nil); err != nil {
+		t.Fatalf("Error al activar: %v", err)
+	}
+	if !servicio.EstaActivo() {
+		t.Error("El módulo debe quedar activo")
+	}
+}
@@ -0,0 +1,403 @@
+package servicios_sanitarios
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icsDateTimeLayout es el formato UTC usado por las propiedades DATE-TIME
+// de RFC 5545 (p.ej. DTSTAMP, CREATED, COMPLETED).
+const icsDateTimeLayout = "20060102T150405Z"
+
+// prioridadAICS traduce la prioridad textual de una Tarea al valor numérico
+// PRIORITY de RFC 5545 (1 = máxima, 9 = mínima).
+var prioridadAICS = map[string]int{
+	"baja":    9,
+	"media":   5,
+	"alta":    3,
+	"critica": 1,
+}
+
+// prioridadDesdeICS hace el camino inverso, agrupando el rango 1-9 en los
+// cuatro niveles que maneja el módulo.
+func prioridadDesdeICS(valor int) string {
+	switch {
+	case valor <= 2:
+		return "critica"
+	case valor <= 4:
+		return "alta"
+	case valor <= 6:
+		return "media"
+	default:
+		return "baja"
+	}
+}
+
+// icsWriter escribe líneas de contenido ICS aplicando el plegado a 75
+// octetos que exige RFC 5545 y acumula el primer error que ocurra.
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (iw *icsWriter) writeLine(linea string) {
+	if iw.err != nil {
+		return
+	}
+
+	restante := linea
+	primera := true
+	for len(restante) > 75 {
+		limite := 75
+		if !primera {
+			limite = 74
+		}
+		if !primera {
+			if _, err := io.WriteString(iw.w, " "); err != nil {
+				iw.err = err
+				return
+			}
+		}
+		if _, err := io.WriteString(iw.w, restante[:limite]+"\r\n"); err != nil {
+			iw.err = err
+			return
+		}
+		restante = restante[limite:]
+		primera = false
+	}
+
+	prefijo := ""
+	if !primera {
+		prefijo = " "
+	}
+	if _, err := io.WriteString(iw.w, prefijo+restante+"\r\n"); err != nil {
+		iw.err = err
+	}
+}
+
+// formatearDuracionICS convierte una time.Duration al formato de duración
+// ISO 8601 que usan los TRIGGER relativos de VALARM (p.ej. -PT1H30M).
+func formatearDuracionICS(d time.Duration) string {
+	signo := ""
+	if d < 0 {
+		signo = "-"
+		d = -d
+	}
+
+	horas := int(d.Hours())
+	minutos := int(d.Minutes()) % 60
+	segundos := int(d.Seconds()) % 60
+
+	var sb strings.Builder
+	sb.WriteString(signo)
+	sb.WriteString("PT")
+	if horas > 0 {
+		fmt.Fprintf(&sb, "%dH", horas)
+	}
+	if minutos > 0 {
+		fmt.Fprintf(&sb, "%dM", minutos)
+	}
+	if segundos > 0 || (horas == 0 && minutos == 0) {
+		fmt.Fprintf(&sb, "%dS", segundos)
+	}
+
+	return sb.String()
+}
+
+// parsearDuracionICS interpreta una duración ISO 8601 (p.ej. -PT1H, PT30M)
+// como time.Duration.
+func parsearDuracionICS(valor string) (time.Duration, error) {
+	signo := time.Duration(1)
+	resto := valor
+	if strings.HasPrefix(resto, "-") {
+		signo = -1
+		resto = resto[1:]
+	} else if strings.HasPrefix(resto, "+") {
+		resto = resto[1:]
+	}
+
+	if !strings.HasPrefix(resto, "PT") {
+		return 0, fmt.Errorf("duración ICS inválida: %s", valor)
+	}
+	resto = resto[2:]
+
+	var total time.Duration
+	numero := ""
+	for _, c := range resto {
+		if c >= '0' && c <= '9' {
+			numero += string(c)
+			continue
+		}
+		n, err := strconv.Atoi(numero)
+		if err != nil {
+			return 0, fmt.Errorf("duración ICS inválida: %s", valor)
+		}
+		switch c {
+		case 'H':
+			total += time.Duration(n) * time.Hour
+		case 'M':
+			total += time.Duration(n) * time.Minute
+		case 'S':
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("duración ICS inválida: %s", valor)
+		}
+		numero = ""
+	}
+
+	return signo * total, nil
+}
+
+// ExportarICS serializa las tareas del módulo como un flujo iCalendar
+// (RFC 5545) con un VTODO por tarea, escribiendo el resultado en w.
+func (s *ServiciosSanitarios) ExportarICS(w io.Writer) error {
+	iw := &icsWriter{w: w}
+
+	iw.writeLine("BEGIN:VCALENDAR")
+	iw.writeLine("VERSION:2.0")
+	iw.writeLine("PRODID:-//Concierge//ServiciosSanitarios//ES")
+	iw.writeLine("CALSCALE:GREGORIAN")
+
+	for _, tarea := range s.ListarTareas("", "") {
+		escribirVTODO(iw, tarea)
+	}
+
+	iw.writeLine("END:VCALENDAR")
+	return iw.err
+}
+
+// EscribirVTODO serializa tarea como un flujo iCalendar con un único
+// VTODO (envuelto en su VCALENDAR), el formato que CalDAV (RFC 4791)
+// espera como calendar-data de cada recurso individual.
+func EscribirVTODO(w io.Writer, tarea Tarea) error {
+	iw := &icsWriter{w: w}
+
+	iw.writeLine("BEGIN:VCALENDAR")
+	iw.writeLine("VERSION:2.0")
+	iw.writeLine("PRODID:-//Concierge//ServiciosSanitarios//ES")
+	iw.writeLine("CALSCALE:GREGORIAN")
+	escribirVTODO(iw, tarea)
+	iw.writeLine("END:VCALENDAR")
+
+	return iw.err
+}
+
+func escribirVTODO(iw *icsWriter, tarea Tarea) {
+	iw.writeLine("BEGIN:VTODO")
+	iw.writeLine("UID:" + tarea.ID)
+	iw.writeLine("DTSTAMP:" + tarea.FechaCreacion.UTC().Format(icsDateTimeLayout))
+	iw.writeLine("CREATED:" + tarea.FechaCreacion.UTC().Format(icsDateTimeLayout))
+	iw.writeLine("SUMMARY:" + escaparTextoICS(tarea.Descripcion))
+
+	prioridad, ok := prioridadAICS[tarea.Prioridad]
+	if !ok {
+		prioridad = prioridadAICS["media"]
+	}
+	iw.writeLine(fmt.Sprintf("PRIORITY:%d", prioridad))
+
+	if tarea.Estado == "completado" {
+		iw.writeLine("STATUS:COMPLETED")
+		if tarea.FechaCompletado != nil {
+			iw.writeLine("COMPLETED:" + tarea.FechaCompletado.UTC().Format(icsDateTimeLayout))
+		}
+	} else {
+		iw.writeLine("STATUS:NEEDS-ACTION")
+	}
+
+	claves := make([]string, 0, len(tarea.Metadata))
+	for clave := range tarea.Metadata {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	for _, clave := range claves {
+		nombre := "X-" + strings.ToUpper(strings.ReplaceAll(clave, " ", "-"))
+		iw.writeLine(nombre + ":" + escaparTextoICS(fmt.Sprintf("%v", tarea.Metadata[clave])))
+	}
+
+	if tarea.Recordatorio != nil {
+		escribirVALARM(iw, tarea)
+	}
+
+	iw.writeLine("END:VTODO")
+}
+
+func escribirVALARM(iw *icsWriter, tarea Tarea) {
+	r := tarea.Recordatorio
+
+	iw.writeLine("BEGIN:VALARM")
+	iw.writeLine("ACTION:DISPLAY")
+
+	switch {
+	case r.Momento != nil:
+		iw.writeLine("TRIGGER;VALUE=DATE-TIME:" + r.Momento.UTC().Format(icsDateTimeLayout))
+	case r.Offset != nil:
+		iw.writeLine("TRIGGER:" + formatearDuracionICS(*r.Offset))
+	default:
+		iw.writeLine("TRIGGER:" + formatearDuracionICS(-time.Hour))
+	}
+
+	descripcion := r.Descripcion
+	if descripcion == "" {
+		descripcion = tarea.Descripcion
+	}
+	iw.writeLine("DESCRIPTION:" + escaparTextoICS(descripcion))
+
+	iw.writeLine("END:VALARM")
+}
+
+func escaparTextoICS(texto string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\n", `\n`,
+	)
+	return r.Replace(texto)
+}
+
+func desescaparTextoICS(texto string) string {
+	r := strings.NewReplacer(
+		`\n`, "\n",
+		`\,`, `,`,
+		`\;`, `;`,
+		`\\`, `\`,
+	)
+	return r.Replace(texto)
+}
+
+// ImportarICS construye un ServiciosSanitarios a partir de un flujo
+// iCalendar, reconstruyendo cada Tarea desde su componente VTODO (y su
+// VALARM, si lo tiene). Es el camino inverso de ExportarICS.
+func ImportarICS(r io.Reader) (*ServiciosSanitarios, error) {
+	s := NewServiciosSanitarios("")
+
+	lineas, err := desplegarLineasICS(r)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer ICS: %w", err)
+	}
+
+	var tareaActual *Tarea
+	var recordatorioActual *Recordatorio
+	dentroDeAlarma := false
+
+	for _, linea := range lineas {
+		switch linea {
+		case "BEGIN:VTODO":
+			tareaActual = &Tarea{
+				Estado:   "pendiente",
+				Metadata: make(map[string]interface{}),
+			}
+			continue
+		case "BEGIN:VALARM":
+			dentroDeAlarma = true
+			recordatorioActual = &Recordatorio{}
+			continue
+		case "END:VALARM":
+			dentroDeAlarma = false
+			if tareaActual != nil {
+				tareaActual.Recordatorio = recordatorioActual
+			}
+			recordatorioActual = nil
+			continue
+		case "END:VTODO":
+			if tareaActual != nil {
+				s.store.Add(*tareaActual)
+			}
+			tareaActual = nil
+			continue
+		}
+
+		nombre, valor := dividirPropiedadICS(linea)
+
+		if tareaActual == nil {
+			continue
+		}
+
+		if dentroDeAlarma {
+			aplicarPropiedadVALARM(recordatorioActual, nombre, valor)
+			continue
+		}
+
+		aplicarPropiedadVTODO(tareaActual, nombre, valor)
+	}
+
+	return s, nil
+}
+
+func aplicarPropiedadVTODO(tarea *Tarea, nombre, valor string) {
+	switch {
+	case nombre == "UID":
+		tarea.ID = valor
+	case nombre == "SUMMARY":
+		tarea.Descripcion = desescaparTextoICS(valor)
+	case nombre == "CREATED" || nombre == "DTSTAMP":
+		if t, err := time.Parse(icsDateTimeLayout, valor); err == nil {
+			tarea.FechaCreacion = t
+		}
+	case nombre == "COMPLETED":
+		if t, err := time.Parse(icsDateTimeLayout, valor); err == nil {
+			tarea.FechaCompletado = &t
+		}
+	case nombre == "STATUS" && valor == "COMPLETED":
+		tarea.Estado = "completado"
+	case nombre == "PRIORITY":
+		if n, err := strconv.Atoi(valor); err == nil {
+			tarea.Prioridad = prioridadDesdeICS(n)
+		}
+	case strings.HasPrefix(nombre, "X-"):
+		clave := strings.ToLower(strings.TrimPrefix(nombre, "X-"))
+		tarea.Metadata[clave] = desescaparTextoICS(valor)
+	}
+}
+
+func aplicarPropiedadVALARM(r *Recordatorio, nombre, valor string) {
+	switch nombre {
+	case "DESCRIPTION":
+		r.Descripcion = desescaparTextoICS(valor)
+	case "TRIGGER;VALUE=DATE-TIME", "TRIGGER":
+		if t, err := time.Parse(icsDateTimeLayout, valor); err == nil {
+			r.Momento = &t
+			return
+		}
+		if d, err := parsearDuracionICS(valor); err == nil {
+			r.Offset = &d
+		}
+	}
+}
+
+// desplegarLineasICS deshace el plegado de líneas de RFC 5545 (una línea
+// continuada comienza con un espacio o tabulador).
+func desplegarLineasICS(r io.Reader) ([]string, error) {
+	var lineas []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		linea := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(linea, " ") || strings.HasPrefix(linea, "\t")) && len(lineas) > 0 {
+			lineas[len(lineas)-1] += linea[1:]
+			continue
+		}
+		lineas = append(lineas, linea)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lineas, nil
+}
+
+// dividirPropiedadICS separa una línea de contenido en nombre de propiedad
+// (incluyendo parámetros, p.ej. "TRIGGER;VALUE=DATE-TIME") y valor.
+func dividirPropiedadICS(linea string) (string, string) {
+	idx := strings.Index(linea, ":")
+	if idx == -1 {
+		return linea, ""
+	}
+	return linea[:idx], linea[idx+1:]
+}
@@ -0,0 +1,113 @@
+package servicios_sanitarios
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportarICSContieneVTODO(t *testing.T) {
+	servicio := NewServiciosSanitarios("")
+	servicio.AgregarTarea("Limpiar estanque de agua potable", "alta", nil)
+
+	var buf bytes.Buffer
+	if err := servicio.ExportarICS(&buf); err != nil {
+		t.Fatalf("Error al exportar ICS: %v", err)
+	}
+
+	salida := buf.String()
+	if !strings.Contains(salida, "BEGIN:VCALENDAR") || !strings.Contains(salida, "END:VCALENDAR") {
+		t.Error("La salida debe estar envuelta en VCALENDAR")
+	}
+	if !strings.Contains(salida, "BEGIN:VTODO") {
+		t.Error("Debe contener al menos un VTODO")
+	}
+	if !strings.Contains(salida, "SUMMARY:Limpiar estanque de agua potable") {
+		t.Error("SUMMARY debe contener la descripción de la tarea")
+	}
+	if !strings.Contains(salida, "PRIORITY:3") {
+		t.Error("Prioridad 'alta' debe exportarse como PRIORITY:3")
+	}
+}
+
+func TestExportarICSConRecordatorio(t *testing.T) {
+	servicio := NewServiciosSanitarios("")
+
+	offset := -time.Hour
+	tarea := Tarea{
+		ID:            GenerateID(),
+		Descripcion:   "Revisar cloro residual",
+		Prioridad:     "critica",
+		Estado:        "pendiente",
+		FechaCreacion: time.Now(),
+		Metadata:      map[string]interface{}{},
+		Recordatorio: &Recordatorio{
+			Offset:      &offset,
+			Descripcion: "Recordatorio: Revisar cloro residual",
+		},
+	}
+	if err := servicio.store.Add(tarea); err != nil {
+		t.Fatalf("Error al agregar tarea: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := servicio.ExportarICS(&buf); err != nil {
+		t.Fatalf("Error al exportar ICS: %v", err)
+	}
+
+	salida := buf.String()
+	if !strings.Contains(salida, "BEGIN:VALARM") {
+		t.Error("Debe contener un VALARM")
+	}
+	if !strings.Contains(salida, "TRIGGER:-PT1H") {
+		t.Error("TRIGGER relativo debe ser -PT1H")
+	}
+}
+
+func TestRoundTripICS(t *testing.T) {
+	original := NewServiciosSanitarios("")
+	original.AgregarTarea("Desinfección de estanques", "media", map[string]interface{}{
+		"ubicacion": "Sector norte",
+	})
+	tarea2, _ := original.AgregarTarea("Emergencia sanitaria piso 4", "critica", nil)
+	original.CompletarTarea(tarea2.ID)
+
+	var buf bytes.Buffer
+	if err := original.ExportarICS(&buf); err != nil {
+		t.Fatalf("Error al exportar ICS: %v", err)
+	}
+
+	importado, err := ImportarICS(&buf)
+	if err != nil {
+		t.Fatalf("Error al importar ICS: %v", err)
+	}
+
+	tareasOriginales := original.ListarTareas("", "")
+	tareasImportadas := importado.ListarTareas("", "")
+
+	if len(tareasImportadas) != len(tareasOriginales) {
+		t.Fatalf("Se esperaban %d tareas, se importaron %d", len(tareasOriginales), len(tareasImportadas))
+	}
+
+	for i, tareaOriginal := range tareasOriginales {
+		tareaImportada := tareasImportadas[i]
+
+		if tareaImportada.ID != tareaOriginal.ID {
+			t.Errorf("ID no coincide: esperado %s, obtenido %s", tareaOriginal.ID, tareaImportada.ID)
+		}
+		if tareaImportada.Descripcion != tareaOriginal.Descripcion {
+			t.Errorf("Descripcion no coincide: esperado %s, obtenido %s", tareaOriginal.Descripcion, tareaImportada.Descripcion)
+		}
+		if tareaImportada.Prioridad != tareaOriginal.Prioridad {
+			t.Errorf("Prioridad no coincide: esperado %s, obtenido %s", tareaOriginal.Prioridad, tareaImportada.Prioridad)
+		}
+		if tareaImportada.Estado != tareaOriginal.Estado {
+			t.Errorf("Estado no coincide: esperado %s, obtenido %s", tareaOriginal.Estado, tareaImportada.Estado)
+		}
+	}
+
+	if tareasImportadas[0].Metadata["ubicacion"] != "Sector norte" {
+		t.Errorf("Metadata X- no se recuperó correctamente: %v", tareasImportadas[0].Metadata["ubicacion"])
+	}
+}
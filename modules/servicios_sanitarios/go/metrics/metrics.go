@@ -0,0 +1,141 @@
+// Package metrics expone los colectores Prometheus del módulo
+// servicios_sanitarios, de modo que el backlog de tareas y las
+// verificaciones SISS sean observables en Grafana sin necesidad de
+// sondear ObtenerEstadisticas por API.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Collectors agrupa los colectores Prometheus registrados para una
+// instancia de ServiciosSanitarios.
+type Collectors struct {
+	TareasTotal                      *prometheus.GaugeVec
+	ModuloActivo                     prometheus.Gauge
+	TareasCreadasTotal               prometheus.Counter
+	TareasCompletadasTotal           prometheus.Counter
+	SISSVerificacionesTotal          *prometheus.CounterVec
+	SISSCambiosDetectadosTotal       *prometheus.CounterVec
+	SISSVerificacionDuracionSegundos prometheus.Histogram
+
+	gatherer prometheus.Gatherer
+}
+
+// NewCollectors crea y registra los colectores del módulo en reg. Si reg
+// es nil, se crea un *prometheus.Registry nuevo en lugar de usar el
+// registrador global por defecto.
+func NewCollectors(reg prometheus.Registerer) *Collectors {
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+
+	c := &Collectors{
+		TareasTotal: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "concierge_tareas_total",
+			Help: "Número de tareas actualmente registradas, por estado y prioridad.",
+		}, []string{"estado", "prioridad"}),
+		ModuloActivo: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "concierge_modulo_activo",
+			Help: "1 si el módulo ServiciosSanitarios está activo, 0 en caso contrario.",
+		}),
+		TareasCreadasTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "concierge_tareas_creadas_total",
+			Help: "Total de tareas creadas desde el arranque del proceso.",
+		}),
+		TareasCompletadasTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "concierge_tareas_completadas_total",
+			Help: "Total de tareas completadas desde el arranque del proceso.",
+		}),
+		SISSVerificacionesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "concierge_siss_verificaciones_total",
+			Help: "Total de verificaciones SISS realizadas, por resultado (exito|fallo).",
+		}, []string{"resultado"}),
+		SISSCambiosDetectadosTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "concierge_siss_cambios_detectados_total",
+			Help: "Total de cambios detectados en una verificación SISS, por campo.",
+		}, []string{"campo"}),
+		SISSVerificacionDuracionSegundos: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "concierge_siss_verificacion_duracion_segundos",
+			Help:    "Duración de una verificación SISS completa, en segundos.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+
+	reg.MustRegister(
+		c.TareasTotal,
+		c.ModuloActivo,
+		c.TareasCreadasTotal,
+		c.TareasCompletadasTotal,
+		c.SISSVerificacionesTotal,
+		c.SISSCambiosDetectadosTotal,
+		c.SISSVerificacionDuracionSegundos,
+	)
+
+	if g, ok := reg.(prometheus.Gatherer); ok {
+		c.gatherer = g
+	}
+
+	return c
+}
+
+// Handler devuelve un http.Handler de promhttp que sirve los colectores
+// del módulo. Si reg no implementaba prometheus.Gatherer (p.ej. un
+// registrador compuesto hecho a mano), cae al registrador global.
+func (c *Collectors) Handler() http.Handler {
+	if c.gatherer != nil {
+		return promhttp.HandlerFor(c.gatherer, promhttp.HandlerOpts{})
+	}
+	return promhttp.Handler()
+}
+
+// RegistrarTareaCreada incrementa el contador de tareas creadas.
+func (c *Collectors) RegistrarTareaCreada() {
+	c.TareasCreadasTotal.Inc()
+}
+
+// RegistrarTareaCompletada incrementa el contador de tareas completadas.
+func (c *Collectors) RegistrarTareaCompletada() {
+	c.TareasCompletadasTotal.Inc()
+}
+
+// IncrementarTareasTotal suma una tarea al gauge de backlog para la
+// combinación estado/prioridad dada.
+func (c *Collectors) IncrementarTareasTotal(estado, prioridad string) {
+	c.TareasTotal.WithLabelValues(estado, prioridad).Inc()
+}
+
+// DecrementarTareasTotal resta una tarea al gauge de backlog para la
+// combinación estado/prioridad dada.
+func (c *Collectors) DecrementarTareasTotal(estado, prioridad string) {
+	c.TareasTotal.WithLabelValues(estado, prioridad).Dec()
+}
+
+// ActualizarModuloActivo refleja en el gauge si el módulo está activo.
+func (c *Collectors) ActualizarModuloActivo(activo bool) {
+	if activo {
+		c.ModuloActivo.Set(1)
+		return
+	}
+	c.ModuloActivo.Set(0)
+}
+
+// RegistrarVerificacionSISS observa la duración de una verificación SISS
+// y suma al contador de resultados ("exito" o "fallo").
+func (c *Collectors) RegistrarVerificacionSISS(segundos float64, exito bool) {
+	resultado := "fallo"
+	if exito {
+		resultado = "exito"
+	}
+	c.SISSVerificacionesTotal.WithLabelValues(resultado).Inc()
+	c.SISSVerificacionDuracionSegundos.Observe(segundos)
+}
+
+// RegistrarCambioDetectado suma al contador de cambios detectados para
+// el campo dado (p.ej. "url_final", "url_tarifas_vigentes").
+func (c *Collectors) RegistrarCambioDetectado(campo string) {
+	c.SISSCambiosDetectadosTotal.WithLabelValues(campo).Inc()
+}
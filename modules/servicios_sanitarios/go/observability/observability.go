@@ -0,0 +1,69 @@
+// Package observability centraliza la configuración de logging
+// estructurado (log/slog) y de trazas distribuidas (OpenTelemetry) de
+// servicios_sanitarios, para que conciergd y el resto de consumidores no
+// tengan que repetir esta inicialización.
+package observability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"golang.org/x/term"
+)
+
+// NombreServicio identifica a servicios_sanitarios como el "service.name"
+// de sus trazas OpenTelemetry.
+const NombreServicio = "concierge-servicios-sanitarios"
+
+// NewLogger crea un *slog.Logger que escribe en out: JSON si out no es
+// una terminal interactiva (el caso normal en producción, donde los logs
+// se recolectan como texto estructurado), o texto legible si out es una
+// terminal, para no tener que leer JSON a mano en desarrollo.
+func NewLogger(out *os.File) *slog.Logger {
+	if term.IsTerminal(int(out.Fd())) {
+		return slog.New(slog.NewTextHandler(out, nil))
+	}
+	return slog.New(slog.NewJSONHandler(out, nil))
+}
+
+// ConfigurarTracerProvider instala, como proveedor global de
+// OpenTelemetry, un *sdktrace.TracerProvider que exporta a
+// OTEL_EXPORTER_OTLP_ENDPOINT (OTLP/HTTP) si esa variable de entorno está
+// definida. Si no lo está, deja el proveedor global sin tocar: queda el
+// no-op por defecto de OpenTelemetry, por lo que otel.Tracer(...) no
+// genera tráfico de red ni overhead, y el uso por CLI permanece
+// silencioso. La función devuelta vacía el exportador antes de terminar
+// el proceso y debe invocarse con defer.
+func ConfigurarTracerProvider(ctx context.Context) (apagar func(context.Context) error, err error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exportador, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("observability: error al crear el exportador OTLP: %w", err)
+	}
+
+	recurso, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(NombreServicio)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("observability: error al construir el resource: %w", err)
+	}
+
+	proveedor := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exportador),
+		sdktrace.WithResource(recurso),
+	)
+	otel.SetTracerProvider(proveedor)
+
+	return proveedor.Shutdown, nil
+}
@@ -0,0 +1,210 @@
+// Package scheduler agenda la verificación periódica de los targets de un
+// config.ScrapeConfig usando expresiones cron, y notifica los cambios
+// detectados a través de implementaciones de Notifier (MQTT, webhook,
+// correo), en lugar de requerir una ejecución manual por demo/cron externo.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/config"
+)
+
+// tracer es el trace.Tracer del scheduler. Cada ciclo de verificación de
+// un target abre aquí su span raíz, del que cuelgan como hijos los spans
+// que Verifier abre dentro de VerificarTarget (redirección, extracción,
+// diff, guardado).
+var tracer = otel.Tracer("github.com/Geek-MD/Concierge/modules/servicios_sanitarios/scheduler")
+
+// Daemon ejecuta periódicamente, con un cron propio por target, las
+// verificaciones de un config.ScrapeConfig, notificando los cambios de
+// URLFinal o URLTarifasVigentes a sus Notifiers.
+type Daemon struct {
+	config    config.ScrapeConfig
+	verifier  *ss.Verifier
+	cron      *cron.Cron
+	notifiers []Notifier
+	metrics   *Metrics
+	logger    *slog.Logger
+
+	// cancel detiene el contexto de los jobs agendados por la última
+	// llamada a Start. Queda nil hasta la primera llamada a Start.
+	cancel context.CancelFunc
+}
+
+// Option configura aspectos opcionales de un Daemon en su construcción.
+type Option func(*Daemon)
+
+// WithNotifiers agrega notifiers a los que el Daemon avisa cuando detecta
+// un cambio en un target.
+func WithNotifiers(notifiers ...Notifier) Option {
+	return func(d *Daemon) {
+		d.notifiers = append(d.notifiers, notifiers...)
+	}
+}
+
+// WithMetrics habilita los colectores Prometheus del Daemon, registrados
+// en reg (un *prometheus.Registry, o nil para crear uno nuevo). Sin esta
+// opción, el Daemon funciona igual pero no expone métricas.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(d *Daemon) {
+		d.metrics = NewMetrics(reg)
+	}
+}
+
+// WithLogger reemplaza el *slog.Logger del Daemon. Si no se usa, se
+// registra con slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Daemon) {
+		d.logger = logger
+	}
+}
+
+// NewDaemon crea un Daemon para cfg. verifier debe haberse construido a
+// partir del mismo cfg (típicamente con ss.NewVerifier(cfg)).
+func NewDaemon(cfg config.ScrapeConfig, verifier *ss.Verifier, opts ...Option) *Daemon {
+	d := &Daemon{
+		config:   cfg,
+		verifier: verifier,
+		cron:     cron.New(),
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(d)
+		}
+	}
+	if d.logger == nil {
+		d.logger = slog.Default()
+	}
+
+	return d
+}
+
+// Start agenda un job por target, usando target.Cron o, si está vacío,
+// "@every <target.Intervalo>", y arranca el cron en segundo plano. Cada
+// job corre sobre un contexto propio de esta llamada a Start, cancelado
+// por Stop.
+func (d *Daemon) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for _, target := range d.config.Targets {
+		target := target
+		if _, err := d.cron.AddFunc(expresionCron(target), func() {
+			d.ejecutarTarget(ctx, target)
+		}); err != nil {
+			cancel()
+			return fmt.Errorf("error al agendar target %q: %w", target.Nombre, err)
+		}
+	}
+
+	d.cancel = cancel
+	d.cron.Start()
+	return nil
+}
+
+// Stop detiene el cron y espera a que los jobs en curso terminen o a que
+// ctx expire, lo que ocurra primero. Si ctx expira primero, cancela el
+// contexto de los jobs en curso para que dejen de trabajar en segundo
+// plano en lugar de seguir corriendo indefinidamente tras el timeout.
+func (d *Daemon) Stop(ctx context.Context) error {
+	detenido := d.cron.Stop()
+	select {
+	case <-detenido.Done():
+		if d.cancel != nil {
+			d.cancel()
+		}
+		return nil
+	case <-ctx.Done():
+		if d.cancel != nil {
+			d.cancel()
+		}
+		return ctx.Err()
+	}
+}
+
+// Handler devuelve el http.Handler de Prometheus del Daemon. Es nil si la
+// instancia no se creó con una opción que habilite métricas.
+func (d *Daemon) Handler() http.Handler {
+	if d.metrics == nil {
+		return nil
+	}
+	return d.metrics.Handler()
+}
+
+// expresionCron devuelve la expresión cron a usar para target: su Cron
+// explícito, o "@every <Intervalo>" si no definió uno.
+func expresionCron(target config.Target) string {
+	if target.Cron != "" {
+		return target.Cron
+	}
+	return fmt.Sprintf("@every %s", target.Intervalo)
+}
+
+// ejecutarTarget verifica target, actualiza las métricas del Daemon y, si
+// cambió la URL final o la de tarifas vigentes, avisa a los Notifiers con
+// los datos guardados por Verifier en target.Almacenamiento.
+func (d *Daemon) ejecutarTarget(ctx context.Context, target config.Target) {
+	ctx, span := tracer.Start(ctx, "scheduler.ciclo", trace.WithAttributes(attribute.String("target", target.Nombre)))
+	defer span.End()
+
+	resultado := d.verifier.VerificarTarget(ctx, target)
+
+	if d.metrics != nil {
+		d.metrics.RegistrarVerificacion(target.Nombre)
+		if !resultado.Exito {
+			d.metrics.RegistrarError(target.Nombre)
+		}
+		for campo, cambio := range resultado.Cambios {
+			if cambio {
+				d.metrics.RegistrarCambio(target.Nombre, campo)
+			}
+		}
+	}
+
+	if !resultado.Exito {
+		err := fmt.Errorf("%s", resultado.Error)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, resultado.Error)
+		d.logger.WarnContext(ctx, "no se pudo verificar el target", "target", target.Nombre, "error", resultado.Error)
+		return
+	}
+
+	if !resultado.Cambios["url_final"] && !resultado.Cambios["url_tarifas_vigentes"] {
+		return
+	}
+
+	datos, ok, err := d.verifier.ObtenerDatos(ctx, target.Almacenamiento)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		d.logger.WarnContext(ctx, "error al leer datos para notificar", "target", target.Nombre, "archivo", target.Almacenamiento, "error", err)
+		return
+	}
+	if !ok {
+		d.logger.WarnContext(ctx, "no hay datos guardados para notificar a pesar de un cambio detectado", "target", target.Nombre, "archivo", target.Almacenamiento)
+		return
+	}
+
+	for _, notifier := range d.notifiers {
+		ctxNotificar, cancel := context.WithTimeout(ctx, target.Timeout)
+		err := notifier.Notificar(ctxNotificar, target.Nombre, datos)
+		cancel()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			d.logger.WarnContext(ctx, "error al notificar", "target", target.Nombre, "error", err)
+		}
+	}
+}
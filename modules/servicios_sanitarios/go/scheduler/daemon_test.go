@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/config"
+)
+
+// notifierFalso registra cada llamada a Notificar, para verificar desde
+// el test a quién y con qué datos avisó el Daemon.
+type notifierFalso struct {
+	mu       sync.Mutex
+	llamadas []ss.DatosVerificacionSISS
+}
+
+func (n *notifierFalso) Notificar(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.llamadas = append(n.llamadas, datos)
+	return nil
+}
+
+func (n *notifierFalso) cantidadLlamadas() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.llamadas)
+}
+
+func TestDaemonEjecutarTargetNotificaEnCambio(t *testing.T) {
+	href := "/tarifas-v1.html"
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="` + href + `">Tarifas vigentes</a></body></html>`))
+	}))
+	defer servidor.Close()
+
+	target := config.Target{
+		Nombre:         "demo",
+		URL:            servidor.URL,
+		Extraccion:     config.ReglaExtraccion{Selector: `a:contains("Tarifas vigentes")`},
+		Almacenamiento: filepath.Join(t.TempDir(), "target.json"),
+		Timeout:        time.Second,
+	}
+	cfg := config.ScrapeConfig{Targets: []config.Target{target}}
+	verifier := ss.NewVerifier(cfg)
+
+	notifier := &notifierFalso{}
+	d := NewDaemon(cfg, verifier, WithNotifiers(notifier))
+
+	// Primera verificación: es la primera vez, no hay "cambio" que notificar.
+	d.ejecutarTarget(context.Background(), target)
+	if notifier.cantidadLlamadas() != 0 {
+		t.Fatalf("no se esperaba notificación en la primera verificación, hubo %d", notifier.cantidadLlamadas())
+	}
+
+	// Cambia el enlace: la segunda verificación debe notificar.
+	href = "/tarifas-v2.html"
+	d.ejecutarTarget(context.Background(), target)
+	if notifier.cantidadLlamadas() != 1 {
+		t.Fatalf("se esperaba 1 notificación tras el cambio, hubo %d", notifier.cantidadLlamadas())
+	}
+
+	// Sin cambios: la tercera verificación no debe notificar de nuevo.
+	d.ejecutarTarget(context.Background(), target)
+	if notifier.cantidadLlamadas() != 1 {
+		t.Fatalf("no se esperaba una nueva notificación sin cambios, hubo %d", notifier.cantidadLlamadas())
+	}
+}
+
+// TestDaemonStopCancelaJobsEnCurso verifica que, si Stop agota su ctx
+// antes de que el cron termine, cancela el contexto del job en curso en
+// lugar de dejarlo corriendo en segundo plano indefinidamente.
+func TestDaemonStopCancelaJobsEnCurso(t *testing.T) {
+	iniciado := make(chan struct{})
+	cancelado := make(chan struct{})
+	bloqueado := make(chan struct{})
+
+	var unaVez sync.Once
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unaVez.Do(func() { close(iniciado) })
+		select {
+		case <-r.Context().Done():
+			close(cancelado)
+		case <-bloqueado:
+		}
+	}))
+	defer servidor.Close()
+	defer close(bloqueado)
+
+	target := config.Target{
+		Nombre:         "lento",
+		URL:            servidor.URL,
+		Extraccion:     config.ReglaExtraccion{Selector: `a:contains("Tarifas vigentes")`},
+		Almacenamiento: filepath.Join(t.TempDir(), "target.json"),
+		Timeout:        time.Hour,
+		Cron:           "@every 10ms",
+	}
+	cfg := config.ScrapeConfig{Targets: []config.Target{target}}
+	verifier := ss.NewVerifier(cfg)
+	d := NewDaemon(cfg, verifier)
+
+	if err := d.Start(); err != nil {
+		t.Fatalf("error al iniciar el daemon: %v", err)
+	}
+
+	select {
+	case <-iniciado:
+	case <-time.After(2 * time.Second):
+		t.Fatal("el target nunca llegó a ejecutarse")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Stop(ctx); err == nil {
+		t.Fatal("se esperaba error por expiración de ctx en Stop")
+	}
+
+	select {
+	case <-cancelado:
+	case <-time.After(time.Second):
+		t.Fatal("el job en curso debió cancelarse al expirar Stop, sigue en curso")
+	}
+}
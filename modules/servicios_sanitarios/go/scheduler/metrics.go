@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics agrupa los colectores Prometheus de un Daemon, con sus
+// verificaciones, errores y cambios detectados contados por target.
+type Metrics struct {
+	VerificacionesTotal *prometheus.CounterVec
+	ErroresTotal        *prometheus.CounterVec
+	CambiosTotal        *prometheus.CounterVec
+
+	gatherer prometheus.Gatherer
+}
+
+// NewMetrics crea y registra los colectores del scheduler en reg. Si reg
+// es nil, se crea un *prometheus.Registry nuevo en lugar de usar el
+// registrador global por defecto.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+
+	m := &Metrics{
+		VerificacionesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "concierge_scheduler_verificaciones_total",
+			Help: "Total de verificaciones ejecutadas por el daemon, por target.",
+		}, []string{"target"}),
+		ErroresTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "concierge_scheduler_errores_total",
+			Help: "Total de verificaciones fallidas ejecutadas por el daemon, por target.",
+		}, []string{"target"}),
+		CambiosTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "concierge_scheduler_cambios_total",
+			Help: "Total de cambios detectados por el daemon, por target y campo.",
+		}, []string{"target", "campo"}),
+	}
+
+	reg.MustRegister(m.VerificacionesTotal, m.ErroresTotal, m.CambiosTotal)
+
+	if g, ok := reg.(prometheus.Gatherer); ok {
+		m.gatherer = g
+	}
+
+	return m
+}
+
+// Handler devuelve un http.Handler de promhttp que sirve los colectores
+// del scheduler. Si reg no implementaba prometheus.Gatherer, cae al
+// registrador global.
+func (m *Metrics) Handler() http.Handler {
+	if m.gatherer != nil {
+		return promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{})
+	}
+	return promhttp.Handler()
+}
+
+// RegistrarVerificacion suma una verificación ejecutada para target.
+func (m *Metrics) RegistrarVerificacion(target string) {
+	m.VerificacionesTotal.WithLabelValues(target).Inc()
+}
+
+// RegistrarError suma una verificación fallida para target.
+func (m *Metrics) RegistrarError(target string) {
+	m.ErroresTotal.WithLabelValues(target).Inc()
+}
+
+// RegistrarCambio suma un cambio detectado en campo para target.
+func (m *Metrics) RegistrarCambio(target, campo string) {
+	m.CambiosTotal.WithLabelValues(target, campo).Inc()
+}
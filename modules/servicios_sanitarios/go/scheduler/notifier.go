@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+// Notifier entrega un aviso de cambio detectado en target, con los datos
+// completos de la verificación (incluido su historial), a un canal externo.
+type Notifier interface {
+	Notificar(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error
+}
+
+// MQTTNotifier publica los datos de verificación en un tópico MQTT por
+// target, reteniendo el último mensaje para que un suscriptor que se
+// conecte después siga viendo la URL vigente.
+type MQTTNotifier struct {
+	cliente        mqtt.Client
+	prefijoTopico  string
+	qos            byte
+	esperaPublicar time.Duration
+}
+
+// NewMQTTNotifier crea un MQTTNotifier que publica en "<prefijoTopico>/<target>"
+// con QoS 1 y el mensaje retenido.
+func NewMQTTNotifier(cliente mqtt.Client, prefijoTopico string) *MQTTNotifier {
+	return &MQTTNotifier{
+		cliente:        cliente,
+		prefijoTopico:  prefijoTopico,
+		qos:            1,
+		esperaPublicar: 10 * time.Second,
+	}
+}
+
+// Notificar publica datos como JSON en el tópico del target, esperando
+// la confirmación del broker hasta n.esperaPublicar o hasta que ctx se
+// cancele, lo que ocurra primero.
+func (n *MQTTNotifier) Notificar(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	payload, err := json.Marshal(datos)
+	if err != nil {
+		return fmt.Errorf("error al serializar datos de verificación: %w", err)
+	}
+
+	topico := fmt.Sprintf("%s/%s", n.prefijoTopico, target)
+	token := n.cliente.Publish(topico, n.qos, true, payload)
+
+	temporizador := time.NewTimer(n.esperaPublicar)
+	defer temporizador.Stop()
+
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("error al publicar en %s: %w", topico, err)
+		}
+		return nil
+	case <-temporizador.C:
+		return fmt.Errorf("tiempo de espera agotado al publicar en %s", topico)
+	case <-ctx.Done():
+		return fmt.Errorf("error al publicar en %s: %w", topico, ctx.Err())
+	}
+}
+
+// WebhookNotifier envía los datos de verificación como un POST JSON a una
+// URL fija, envolviéndolos junto con el nombre del target.
+type WebhookNotifier struct {
+	url     string
+	cliente *http.Client
+}
+
+// NewWebhookNotifier crea un WebhookNotifier que publica en url.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, cliente: http.DefaultClient}
+}
+
+// cuerpoWebhook es el cuerpo JSON enviado por WebhookNotifier, que además
+// del target identifica qué verificación originó el aviso.
+type cuerpoWebhook struct {
+	Target string `json:"target"`
+	ss.DatosVerificacionSISS
+}
+
+// Notificar envía datos como un POST JSON a n.url.
+func (n *WebhookNotifier) Notificar(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	payload, err := json.Marshal(cuerpoWebhook{Target: target, DatosVerificacionSISS: datos})
+	if err != nil {
+		return fmt.Errorf("error al serializar datos de verificación: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error al construir la solicitud: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.cliente.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al enviar webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondió con estado %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// EmailNotifier envía los datos de verificación por correo vía SMTP.
+type EmailNotifier struct {
+	servidorSMTP  string
+	auth          smtp.Auth
+	remitente     string
+	destinatarios []string
+}
+
+// NewEmailNotifier crea un EmailNotifier que envía desde remitente a
+// destinatarios usando el servidor SMTP en servidorSMTP (host:puerto). auth
+// puede ser nil si el servidor no requiere autenticación.
+func NewEmailNotifier(servidorSMTP string, auth smtp.Auth, remitente string, destinatarios []string) *EmailNotifier {
+	return &EmailNotifier{
+		servidorSMTP:  servidorSMTP,
+		auth:          auth,
+		remitente:     remitente,
+		destinatarios: destinatarios,
+	}
+}
+
+// Notificar envía un correo con los datos de verificación como cuerpo.
+func (n *EmailNotifier) Notificar(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	cuerpoJSON, err := json.MarshalIndent(datos, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar datos de verificación: %w", err)
+	}
+
+	mensaje := fmt.Sprintf(
+		"Subject: [concierge] Cambio detectado en %s\r\n\r\n%s\r\n",
+		target, cuerpoJSON,
+	)
+
+	if err := enviarCorreo(ctx, n.servidorSMTP, n.auth, n.remitente, n.destinatarios, []byte(mensaje)); err != nil {
+		return fmt.Errorf("error al enviar correo: %w", err)
+	}
+
+	return nil
+}
+
+// enviarCorreo reimplementa smtp.SendMail (conexión, STARTTLS si el
+// servidor lo ofrece, autenticación, MAIL/RCPT/DATA) sobre una conexión
+// abierta con DialContext, para que una cancelación de ctx corte la
+// conexión de inmediato en lugar de dejarla (y su goroutine) esperando
+// indefinidamente a un servidor lento o colgado.
+func enviarCorreo(ctx context.Context, servidorSMTP string, auth smtp.Auth, remitente string, destinatarios []string, mensaje []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", servidorSMTP)
+	if err != nil {
+		return fmt.Errorf("error al conectar con el servidor SMTP: %w", err)
+	}
+
+	cerrado := make(chan struct{})
+	defer close(cerrado)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-cerrado:
+		}
+	}()
+
+	host, _, err := net.SplitHostPort(servidorSMTP)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error al interpretar el servidor SMTP %q: %w", servidorSMTP, err)
+	}
+
+	cliente, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error al iniciar sesión SMTP: %w", err)
+	}
+	defer cliente.Close()
+
+	if ok, _ := cliente.Extension("STARTTLS"); ok {
+		if err := cliente.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("error al negociar STARTTLS: %w", err)
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := cliente.Extension("AUTH"); !ok {
+			return fmt.Errorf("el servidor SMTP no admite autenticación")
+		}
+		if err := cliente.Auth(auth); err != nil {
+			return fmt.Errorf("error al autenticar: %w", err)
+		}
+	}
+
+	if err := cliente.Mail(remitente); err != nil {
+		return err
+	}
+	for _, destinatario := range destinatarios {
+		if err := cliente.Rcpt(destinatario); err != nil {
+			return err
+		}
+	}
+
+	w, err := cliente.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(mensaje); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return cliente.Quit()
+}
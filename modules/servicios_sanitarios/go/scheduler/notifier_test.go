@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+func TestWebhookNotifierNotificarEnviaPOST(t *testing.T) {
+	var recibido cuerpoWebhook
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("método esperado POST, obtenido %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&recibido); err != nil {
+			t.Fatalf("error al decodificar el cuerpo: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer servidor.Close()
+
+	n := NewWebhookNotifier(servidor.URL)
+	datos := ss.DatosVerificacionSISS{URLFinal: "https://example.com/tarifas"}
+	if err := n.Notificar(context.Background(), "demo", datos); err != nil {
+		t.Fatalf("error inesperado al notificar: %v", err)
+	}
+
+	if recibido.Target != "demo" {
+		t.Errorf("Target esperado %q, obtenido %q", "demo", recibido.Target)
+	}
+	if recibido.URLFinal != datos.URLFinal {
+		t.Errorf("URLFinal esperado %q, obtenido %q", datos.URLFinal, recibido.URLFinal)
+	}
+}
+
+func TestWebhookNotifierNotificarErrorEnRespuesta(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer servidor.Close()
+
+	n := NewWebhookNotifier(servidor.URL)
+	if err := n.Notificar(context.Background(), "demo", ss.DatosVerificacionSISS{}); err == nil {
+		t.Fatal("se esperaba error ante una respuesta 500 del webhook")
+	}
+}
+
+// servidorSMTPFalso implementa lo mínimo del protocolo SMTP (sin
+// STARTTLS ni AUTH) para que EmailNotifier pueda enviar un correo de
+// punta a punta contra él; cada mensaje recibido se publica en mensajes.
+func servidorSMTPFalso(t *testing.T, mensajes chan<- string) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error al escuchar: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go atenderSMTPFalso(conn, mensajes)
+		}
+	}()
+
+	return listener
+}
+
+func atenderSMTPFalso(conn net.Conn, mensajes chan<- string) {
+	defer conn.Close()
+	texto := textproto.NewConn(conn)
+	texto.PrintfLine("220 localhost ESMTP")
+
+	var cuerpo strings.Builder
+	leyendoDatos := false
+	for {
+		linea, err := texto.ReadLine()
+		if err != nil {
+			return
+		}
+		if leyendoDatos {
+			if linea == "." {
+				texto.PrintfLine("250 OK")
+				mensajes <- cuerpo.String()
+				leyendoDatos = false
+				cuerpo.Reset()
+				continue
+			}
+			cuerpo.WriteString(linea + "\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(linea, "EHLO"), strings.HasPrefix(linea, "HELO"):
+			texto.PrintfLine("250 localhost")
+		case strings.HasPrefix(linea, "MAIL FROM"):
+			texto.PrintfLine("250 OK")
+		case strings.HasPrefix(linea, "RCPT TO"):
+			texto.PrintfLine("250 OK")
+		case linea == "DATA":
+			texto.PrintfLine("354 Start mail input")
+			leyendoDatos = true
+		case linea == "QUIT":
+			texto.PrintfLine("221 Bye")
+			return
+		default:
+			texto.PrintfLine("500 comando no reconocido")
+		}
+	}
+}
+
+func TestEmailNotifierNotificarEnviaCorreo(t *testing.T) {
+	mensajes := make(chan string, 1)
+	listener := servidorSMTPFalso(t, mensajes)
+	defer listener.Close()
+
+	n := NewEmailNotifier(listener.Addr().String(), nil, "origen@example.com", []string{"destino@example.com"})
+
+	datos := ss.DatosVerificacionSISS{URLFinal: "https://example.com/tarifas"}
+	if err := n.Notificar(context.Background(), "demo", datos); err != nil {
+		t.Fatalf("error inesperado al notificar: %v", err)
+	}
+
+	select {
+	case cuerpo := <-mensajes:
+		if !strings.Contains(cuerpo, "https://example.com/tarifas") {
+			t.Errorf("el cuerpo del correo no contiene la URL verificada: %q", cuerpo)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("el servidor SMTP falso nunca recibió el mensaje")
+	}
+}
+
+func TestEmailNotifierNotificarRespetaCtx(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error al escuchar: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Nunca escribe el banner 220: simula un servidor SMTP colgado.
+		select {}
+	}()
+
+	n := NewEmailNotifier(listener.Addr().String(), nil, "origen@example.com", []string{"destino@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	inicio := time.Now()
+	err = n.Notificar(ctx, "demo", ss.DatosVerificacionSISS{})
+	duracion := time.Since(inicio)
+
+	if err == nil {
+		t.Fatal("se esperaba error por cancelación del context")
+	}
+	if duracion > 2*time.Second {
+		t.Errorf("Notificar tardó %v, se esperaba que respetara el timeout de ctx", duracion)
+	}
+}
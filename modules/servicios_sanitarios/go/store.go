@@ -0,0 +1,131 @@
+package servicios_sanitarios
+
+import (
+	"sync"
+	"time"
+)
+
+// TareaStore abstrae el almacenamiento de Tareas para que ServiciosSanitarios
+// pueda respaldarse en distintos backends (memoria, SQLite, Postgres) sin
+// cambiar su API pública. Las implementaciones SQL viven en el subpaquete
+// store.
+type TareaStore interface {
+	Add(tarea Tarea) error
+	Get(id string) (Tarea, bool, error)
+	List(filtroEstado, filtroPrioridad string) ([]Tarea, error)
+	// Complete marca como completada la tarea id, registrando momento como
+	// su FechaCompletado. El llamador decide momento (en vez de que la
+	// implementación use time.Now()) para respetar el reloj y la zona
+	// horaria configurados en ServiciosSanitarios.
+	Complete(id string, momento time.Time) (bool, error)
+	Count() (int, error)
+	Stats() (Estadisticas, error)
+}
+
+// MemoriaTareaStore es la implementación por defecto de TareaStore: guarda
+// las tareas en un slice en memoria protegido por un sync.RWMutex. A
+// diferencia del slice sin proteger que usaba ServiciosSanitarios
+// originalmente, es seguro para acceso concurrente.
+type MemoriaTareaStore struct {
+	mu     sync.RWMutex
+	tareas []Tarea
+}
+
+// NewMemoriaTareaStore crea un MemoriaTareaStore vacío.
+func NewMemoriaTareaStore() *MemoriaTareaStore {
+	return &MemoriaTareaStore{}
+}
+
+// Add agrega tarea al store.
+func (m *MemoriaTareaStore) Add(tarea Tarea) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tareas = append(m.tareas, tarea)
+	return nil
+}
+
+// Get busca una tarea por ID.
+func (m *MemoriaTareaStore) Get(id string) (Tarea, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, tarea := range m.tareas {
+		if tarea.ID == id {
+			return tarea, true, nil
+		}
+	}
+	return Tarea{}, false, nil
+}
+
+// List devuelve las tareas que matchean los filtros dados (vacíos = sin filtrar).
+func (m *MemoriaTareaStore) List(filtroEstado, filtroPrioridad string) ([]Tarea, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var resultado []Tarea
+	for _, tarea := range m.tareas {
+		if filtroEstado != "" && tarea.Estado != filtroEstado {
+			continue
+		}
+		if filtroPrioridad != "" && tarea.Prioridad != filtroPrioridad {
+			continue
+		}
+		resultado = append(resultado, tarea)
+	}
+	return resultado, nil
+}
+
+// Complete marca como completada la tarea con el ID dado.
+func (m *MemoriaTareaStore) Complete(id string, momento time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.tareas {
+		if m.tareas[i].ID == id {
+			m.tareas[i].Estado = "completado"
+			m.tareas[i].FechaCompletado = &momento
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Count devuelve el número total de tareas almacenadas.
+func (m *MemoriaTareaStore) Count() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.tareas), nil
+}
+
+// Stats calcula los conteos de Estadisticas a partir de las tareas
+// almacenadas. ModuloActivo y FechaCreacionModulo quedan en su valor cero;
+// ServiciosSanitarios.ObtenerEstadisticas los completa.
+func (m *MemoriaTareaStore) Stats() (Estadisticas, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := Estadisticas{Total: len(m.tareas)}
+
+	for _, tarea := range m.tareas {
+		if tarea.Estado == "pendiente" {
+			stats.Pendientes++
+		} else if tarea.Estado == "completado" {
+			stats.Completadas++
+		}
+
+		switch tarea.Prioridad {
+		case "baja":
+			stats.PorPrioridad.Baja++
+		case "media":
+			stats.PorPrioridad.Media++
+		case "alta":
+			stats.PorPrioridad.Alta++
+		case "critica":
+			stats.PorPrioridad.Critica++
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+// JSONStore implementa ss.Store guardando cada target en su propio
+// archivo JSON (el mismo formato que usaban CargarJSON/GuardarJSON) bajo
+// directorio, y las tareas en un snapshot en directorio/tareas.json. Se
+// mantiene por compatibilidad hacia atrás para despliegues de un solo
+// proceso que no necesitan una base de datos.
+type JSONStore struct {
+	mu         sync.RWMutex
+	directorio string
+	tareas     []ss.Tarea
+}
+
+// NewJSONStore crea un JSONStore que persiste bajo directorio, cargando
+// las tareas guardadas en una ejecución previa si existen.
+func NewJSONStore(directorio string) *JSONStore {
+	s := &JSONStore{directorio: directorio}
+
+	var tareas []ss.Tarea
+	if err := ss.CargarJSON(s.archivoTareas(), &tareas); err == nil {
+		s.tareas = tareas
+	}
+
+	return s
+}
+
+func (s *JSONStore) archivoTareas() string {
+	return filepath.Join(s.directorio, "tareas.json")
+}
+
+func (s *JSONStore) archivoVerificacion(target string) string {
+	return filepath.Join(s.directorio, "verificaciones", target+".json")
+}
+
+// Add agrega tarea y persiste el snapshot de tareas.
+func (s *JSONStore) Add(tarea ss.Tarea) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tareas = append(s.tareas, tarea)
+	return ss.GuardarJSON(s.tareas, s.archivoTareas())
+}
+
+// Get busca una tarea por ID.
+func (s *JSONStore) Get(id string) (ss.Tarea, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, tarea := range s.tareas {
+		if tarea.ID == id {
+			return tarea, true, nil
+		}
+	}
+	return ss.Tarea{}, false, nil
+}
+
+// List devuelve las tareas que matchean los filtros dados (vacíos = sin filtrar).
+func (s *JSONStore) List(filtroEstado, filtroPrioridad string) ([]ss.Tarea, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var resultado []ss.Tarea
+	for _, tarea := range s.tareas {
+		if filtroEstado != "" && tarea.Estado != filtroEstado {
+			continue
+		}
+		if filtroPrioridad != "" && tarea.Prioridad != filtroPrioridad {
+			continue
+		}
+		resultado = append(resultado, tarea)
+	}
+	return resultado, nil
+}
+
+// Complete marca como completada la tarea con el ID dado y persiste el
+// snapshot de tareas.
+func (s *JSONStore) Complete(id string, momento time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.tareas {
+		if s.tareas[i].ID == id {
+			s.tareas[i].Estado = "completado"
+			s.tareas[i].FechaCompletado = &momento
+			return true, ss.GuardarJSON(s.tareas, s.archivoTareas())
+		}
+	}
+	return false, nil
+}
+
+// Count devuelve el número total de tareas almacenadas.
+func (s *JSONStore) Count() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.tareas), nil
+}
+
+// Stats calcula los conteos de ss.Estadisticas a partir de las tareas almacenadas.
+func (s *JSONStore) Stats() (ss.Estadisticas, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := ss.Estadisticas{Total: len(s.tareas)}
+	for _, tarea := range s.tareas {
+		switch tarea.Estado {
+		case "pendiente":
+			stats.Pendientes++
+		case "completado":
+			stats.Completadas++
+		}
+		switch tarea.Prioridad {
+		case "baja":
+			stats.PorPrioridad.Baja++
+		case "media":
+			stats.PorPrioridad.Media++
+		case "alta":
+			stats.PorPrioridad.Alta++
+		case "critica":
+			stats.PorPrioridad.Critica++
+		}
+	}
+	return stats, nil
+}
+
+// SaveVerificacion guarda datos como el archivo JSON de target.
+func (s *JSONStore) SaveVerificacion(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	return ss.GuardarJSON(datos, s.archivoVerificacion(target))
+}
+
+// LoadLatest lee el archivo JSON de target.
+func (s *JSONStore) LoadLatest(ctx context.Context, target string) (ss.DatosVerificacionSISS, bool, error) {
+	var datos ss.DatosVerificacionSISS
+	if err := ss.CargarJSON(s.archivoVerificacion(target), &datos); err != nil {
+		return ss.DatosVerificacionSISS{}, false, nil
+	}
+	return datos, true, nil
+}
+
+// AppendHistorial agrega entrada al historial guardado de target, sin
+// tocar el resto de su resultado vigente.
+func (s *JSONStore) AppendHistorial(ctx context.Context, target string, entrada ss.EntradaHistorial) error {
+	var datos ss.DatosVerificacionSISS
+	_ = ss.CargarJSON(s.archivoVerificacion(target), &datos)
+
+	datos.Historial = append(datos.Historial, entrada)
+	return ss.GuardarJSON(datos, s.archivoVerificacion(target))
+}
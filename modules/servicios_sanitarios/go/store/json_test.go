@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+func TestJSONStoreTareas(t *testing.T) {
+	s := NewJSONStore(t.TempDir())
+
+	tarea := ss.Tarea{
+		ID:            "1",
+		Descripcion:   "Revisar medidor",
+		Prioridad:     "alta",
+		Estado:        "pendiente",
+		FechaCreacion: time.Now(),
+	}
+	if err := s.Add(tarea); err != nil {
+		t.Fatalf("error al agregar tarea: %v", err)
+	}
+
+	momento := time.Now()
+	completada, err := s.Complete("1", momento)
+	if err != nil || !completada {
+		t.Fatalf("se esperaba completar la tarea, completada=%v err=%v", completada, err)
+	}
+
+	// Un nuevo JSONStore sobre el mismo directorio debe ver la tarea
+	// persistida y completada.
+	s2 := NewJSONStore(s.directorio)
+	obtenida, encontrada, err := s2.Get("1")
+	if err != nil || !encontrada {
+		t.Fatalf("no se encontró la tarea persistida: encontrada=%v err=%v", encontrada, err)
+	}
+	if obtenida.Estado != "completado" {
+		t.Errorf("estado esperado 'completado', obtenido %q", obtenida.Estado)
+	}
+}
+
+func TestJSONStoreVerificaciones(t *testing.T) {
+	ctx := context.Background()
+	s := NewJSONStore(t.TempDir())
+
+	if _, ok, err := s.LoadLatest(ctx, "siss"); err != nil || ok {
+		t.Fatalf("no debía haber resultado previo: ok=%v err=%v", ok, err)
+	}
+
+	datos := ss.DatosVerificacionSISS{
+		URLOriginal: "https://www.siss.gob.cl",
+		URLFinal:    "https://www.siss.gob.cl/586/w3-channel.html",
+		Timestamp:   ss.FormatTimestamp(time.Now()),
+		Verificado:  true,
+	}
+	if err := s.SaveVerificacion(ctx, "siss", datos); err != nil {
+		t.Fatalf("error al guardar verificación: %v", err)
+	}
+
+	cargados, ok, err := s.LoadLatest(ctx, "siss")
+	if err != nil || !ok {
+		t.Fatalf("se esperaba encontrar el resultado guardado: ok=%v err=%v", ok, err)
+	}
+	if cargados.URLFinal != datos.URLFinal {
+		t.Errorf("URLFinal esperado %q, obtenido %q", datos.URLFinal, cargados.URLFinal)
+	}
+
+	entrada := ss.EntradaHistorial{URLFinal: datos.URLFinal, Timestamp: datos.Timestamp}
+	if err := s.AppendHistorial(ctx, "siss", entrada); err != nil {
+		t.Fatalf("error al agregar historial: %v", err)
+	}
+
+	cargados, _, err = s.LoadLatest(ctx, "siss")
+	if err != nil {
+		t.Fatalf("error al recargar verificación: %v", err)
+	}
+	if len(cargados.Historial) != 1 {
+		t.Errorf("se esperaba 1 entrada de historial, hay %d", len(cargados.Historial))
+	}
+}
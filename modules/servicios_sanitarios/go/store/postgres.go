@@ -0,0 +1,421 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+const esquemaPostgres = `
+CREATE TABLE IF NOT EXISTS tareas (
+	id TEXT PRIMARY KEY,
+	descripcion TEXT NOT NULL,
+	prioridad TEXT NOT NULL,
+	estado TEXT NOT NULL,
+	fecha_creacion TIMESTAMPTZ NOT NULL,
+	fecha_completado TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS tareas_metadata (
+	tarea_id TEXT NOT NULL REFERENCES tareas(id) ON DELETE CASCADE,
+	clave TEXT NOT NULL,
+	valor TEXT NOT NULL,
+	PRIMARY KEY (tarea_id, clave)
+);
+
+CREATE TABLE IF NOT EXISTS targets (
+	id SERIAL PRIMARY KEY,
+	nombre TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS verificaciones (
+	target_id INTEGER PRIMARY KEY REFERENCES targets(id) ON DELETE CASCADE,
+	url_original TEXT NOT NULL,
+	url_final TEXT NOT NULL,
+	url_tarifas_vigentes TEXT,
+	timestamp TIMESTAMPTZ NOT NULL,
+	zona TEXT NOT NULL,
+	metadata JSONB,
+	changed_fields SMALLINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS historial (
+	id SERIAL PRIMARY KEY,
+	target_id INTEGER NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+	url_final TEXT NOT NULL,
+	url_tarifas_vigentes TEXT,
+	timestamp TIMESTAMPTZ NOT NULL,
+	zona TEXT NOT NULL
+);
+`
+
+// PostgresTareaStore implementa ss.Store (tareas y verificaciones de
+// targets) sobre Postgres, vía database/sql y el driver github.com/lib/pq.
+type PostgresTareaStore struct {
+	db *sql.DB
+}
+
+// NewPostgresTareaStore abre una conexión a dsn y aplica las migraciones de esquema.
+func NewPostgresTareaStore(dsn string) (*PostgresTareaStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar a Postgres: %w", err)
+	}
+
+	if _, err := db.Exec(esquemaPostgres); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al migrar esquema Postgres: %w", err)
+	}
+
+	return &PostgresTareaStore{db: db}, nil
+}
+
+// Close cierra la conexión a la base de datos.
+func (p *PostgresTareaStore) Close() error {
+	return p.db.Close()
+}
+
+func (p *PostgresTareaStore) idTarget(target string) (int64, error) {
+	if id, err := p.buscarIDTarget(target); err == nil {
+		return id, nil
+	}
+
+	if _, err := p.db.Exec(`INSERT INTO targets (nombre) VALUES ($1) ON CONFLICT (nombre) DO NOTHING`, target); err != nil {
+		return 0, fmt.Errorf("error al registrar target %q: %w", target, err)
+	}
+	return p.buscarIDTarget(target)
+}
+
+func (p *PostgresTareaStore) buscarIDTarget(target string) (int64, error) {
+	var id int64
+	err := p.db.QueryRow(`SELECT id FROM targets WHERE nombre = $1`, target).Scan(&id)
+	return id, err
+}
+
+// SaveVerificacion actualiza el resultado vigente de target y reemplaza su
+// historial por el contenido de datos.Historial.
+func (p *PostgresTareaStore) SaveVerificacion(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	idTarget, err := p.idTarget(target)
+	if err != nil {
+		return err
+	}
+
+	var anterior ss.DatosVerificacionSISS
+	huboAnterior := p.db.QueryRowContext(ctx,
+		`SELECT url_final, url_tarifas_vigentes FROM verificaciones WHERE target_id = $1`, idTarget,
+	).Scan(&anterior.URLFinal, &anterior.URLTarifasVigentes) == nil
+	cambios := calcularCambios(huboAnterior, anterior.URLFinal, anterior.URLTarifasVigentes, datos.URLFinal, datos.URLTarifasVigentes)
+
+	metadata, err := json.Marshal(datos.Labels)
+	if err != nil {
+		return fmt.Errorf("error al serializar metadata: %w", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO verificaciones (target_id, url_original, url_final, url_tarifas_vigentes, timestamp, zona, metadata, changed_fields)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (target_id) DO UPDATE SET
+			url_original = excluded.url_original,
+			url_final = excluded.url_final,
+			url_tarifas_vigentes = excluded.url_tarifas_vigentes,
+			timestamp = excluded.timestamp,
+			zona = excluded.zona,
+			metadata = excluded.metadata,
+			changed_fields = excluded.changed_fields`,
+		idTarget, datos.URLOriginal, datos.URLFinal, datos.URLTarifasVigentes, datos.Timestamp, datos.Zona, string(metadata), cambios,
+	)
+	if err != nil {
+		return fmt.Errorf("error al guardar verificación de %q: %w", target, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM historial WHERE target_id = $1`, idTarget); err != nil {
+		return fmt.Errorf("error al limpiar historial de %q: %w", target, err)
+	}
+	for _, entrada := range datos.Historial {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO historial (target_id, url_final, url_tarifas_vigentes, timestamp, zona) VALUES ($1, $2, $3, $4, $5)`,
+			idTarget, entrada.URLFinal, entrada.URLTarifasVigentes, entrada.Timestamp, entrada.Zona,
+		); err != nil {
+			return fmt.Errorf("error al guardar historial de %q: %w", target, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LoadLatest devuelve el resultado vigente de target junto con su historial.
+func (p *PostgresTareaStore) LoadLatest(ctx context.Context, target string) (ss.DatosVerificacionSISS, bool, error) {
+	idTarget, err := p.buscarIDTarget(target)
+	if err == sql.ErrNoRows {
+		return ss.DatosVerificacionSISS{}, false, nil
+	}
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al buscar target %q: %w", target, err)
+	}
+
+	var datos ss.DatosVerificacionSISS
+	var metadata sql.NullString
+	err = p.db.QueryRowContext(ctx,
+		`SELECT url_original, url_final, url_tarifas_vigentes, timestamp, zona, metadata FROM verificaciones WHERE target_id = $1`, idTarget,
+	).Scan(&datos.URLOriginal, &datos.URLFinal, &datos.URLTarifasVigentes, &datos.Timestamp, &datos.Zona, &metadata)
+	if err == sql.ErrNoRows {
+		return ss.DatosVerificacionSISS{}, false, nil
+	}
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al leer verificación de %q: %w", target, err)
+	}
+	datos.Verificado = true
+
+	if metadata.Valid && metadata.String != "" {
+		if err := json.Unmarshal([]byte(metadata.String), &datos.Labels); err != nil {
+			return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al deserializar metadata de %q: %w", target, err)
+		}
+	}
+
+	historial, err := p.cargarHistorialVerificacion(ctx, idTarget)
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, err
+	}
+	datos.Historial = historial
+
+	return datos, true, nil
+}
+
+// AppendHistorial agrega entrada al historial de target, sin tocar su
+// resultado vigente.
+func (p *PostgresTareaStore) AppendHistorial(ctx context.Context, target string, entrada ss.EntradaHistorial) error {
+	idTarget, err := p.idTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.db.ExecContext(ctx,
+		`INSERT INTO historial (target_id, url_final, url_tarifas_vigentes, timestamp, zona) VALUES ($1, $2, $3, $4, $5)`,
+		idTarget, entrada.URLFinal, entrada.URLTarifasVigentes, entrada.Timestamp, entrada.Zona,
+	); err != nil {
+		return fmt.Errorf("error al guardar historial de %q: %w", target, err)
+	}
+	return nil
+}
+
+func (p *PostgresTareaStore) cargarHistorialVerificacion(ctx context.Context, idTarget int64) ([]ss.EntradaHistorial, error) {
+	filas, err := p.db.QueryContext(ctx,
+		`SELECT url_final, url_tarifas_vigentes, timestamp, zona FROM historial WHERE target_id = $1 ORDER BY id`, idTarget,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer historial: %w", err)
+	}
+	defer filas.Close()
+
+	var historial []ss.EntradaHistorial
+	for filas.Next() {
+		var entrada ss.EntradaHistorial
+		if err := filas.Scan(&entrada.URLFinal, &entrada.URLTarifasVigentes, &entrada.Timestamp, &entrada.Zona); err != nil {
+			return nil, fmt.Errorf("error al leer historial: %w", err)
+		}
+		historial = append(historial, entrada)
+	}
+	return historial, filas.Err()
+}
+
+// Add inserta tarea y su metadata en sendas tablas, dentro de una transacción.
+func (p *PostgresTareaStore) Add(tarea ss.Tarea) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fechaCompletado interface{}
+	if tarea.FechaCompletado != nil {
+		fechaCompletado = *tarea.FechaCompletado
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO tareas (id, descripcion, prioridad, estado, fecha_creacion, fecha_completado) VALUES ($1, $2, $3, $4, $5, $6)`,
+		tarea.ID, tarea.Descripcion, tarea.Prioridad, tarea.Estado, tarea.FechaCreacion, fechaCompletado,
+	)
+	if err != nil {
+		return fmt.Errorf("error al insertar tarea: %w", err)
+	}
+
+	for clave, valor := range tarea.Metadata {
+		valorJSON, err := json.Marshal(valor)
+		if err != nil {
+			return fmt.Errorf("error al serializar metadata %q: %w", clave, err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO tareas_metadata (tarea_id, clave, valor) VALUES ($1, $2, $3)`,
+			tarea.ID, clave, string(valorJSON),
+		); err != nil {
+			return fmt.Errorf("error al insertar metadata %q: %w", clave, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get busca una tarea por ID.
+func (p *PostgresTareaStore) Get(id string) (ss.Tarea, bool, error) {
+	fila := p.db.QueryRow(
+		`SELECT id, descripcion, prioridad, estado, fecha_creacion, fecha_completado FROM tareas WHERE id = $1`, id,
+	)
+
+	var tarea ss.Tarea
+	var fechaCompletado sql.NullTime
+
+	err := fila.Scan(&tarea.ID, &tarea.Descripcion, &tarea.Prioridad, &tarea.Estado, &tarea.FechaCreacion, &fechaCompletado)
+	if err == sql.ErrNoRows {
+		return ss.Tarea{}, false, nil
+	}
+	if err != nil {
+		return ss.Tarea{}, false, fmt.Errorf("error al leer tarea: %w", err)
+	}
+	if fechaCompletado.Valid {
+		tarea.FechaCompletado = &fechaCompletado.Time
+	}
+
+	metadata, err := p.cargarMetadata(id)
+	if err != nil {
+		return ss.Tarea{}, false, err
+	}
+	tarea.Metadata = metadata
+
+	return tarea, true, nil
+}
+
+// List devuelve las tareas que matchean los filtros dados (vacíos = sin filtrar).
+func (p *PostgresTareaStore) List(filtroEstado, filtroPrioridad string) ([]ss.Tarea, error) {
+	consulta := `SELECT id, descripcion, prioridad, estado, fecha_creacion, fecha_completado FROM tareas WHERE TRUE`
+	var args []interface{}
+
+	if filtroEstado != "" {
+		args = append(args, filtroEstado)
+		consulta += fmt.Sprintf(" AND estado = $%d", len(args))
+	}
+	if filtroPrioridad != "" {
+		args = append(args, filtroPrioridad)
+		consulta += fmt.Sprintf(" AND prioridad = $%d", len(args))
+	}
+
+	filas, err := p.db.Query(consulta, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar tareas: %w", err)
+	}
+	defer filas.Close()
+
+	var resultado []ss.Tarea
+	for filas.Next() {
+		var tarea ss.Tarea
+		var fechaCompletado sql.NullTime
+
+		if err := filas.Scan(&tarea.ID, &tarea.Descripcion, &tarea.Prioridad, &tarea.Estado, &tarea.FechaCreacion, &fechaCompletado); err != nil {
+			return nil, fmt.Errorf("error al leer tarea: %w", err)
+		}
+		if fechaCompletado.Valid {
+			tarea.FechaCompletado = &fechaCompletado.Time
+		}
+
+		metadata, err := p.cargarMetadata(tarea.ID)
+		if err != nil {
+			return nil, err
+		}
+		tarea.Metadata = metadata
+
+		resultado = append(resultado, tarea)
+	}
+
+	return resultado, filas.Err()
+}
+
+// Complete marca como completada la tarea con el ID dado.
+func (p *PostgresTareaStore) Complete(id string, momento time.Time) (bool, error) {
+	res, err := p.db.Exec(
+		`UPDATE tareas SET estado = 'completado', fecha_completado = $1 WHERE id = $2`,
+		momento, id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error al completar tarea: %w", err)
+	}
+
+	filas, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	return filas > 0, nil
+}
+
+// Count devuelve el número total de tareas almacenadas.
+func (p *PostgresTareaStore) Count() (int, error) {
+	var total int
+	if err := p.db.QueryRow(`SELECT COUNT(*) FROM tareas`).Scan(&total); err != nil {
+		return 0, fmt.Errorf("error al contar tareas: %w", err)
+	}
+	return total, nil
+}
+
+// Stats calcula los conteos de ss.Estadisticas a partir de la tabla de tareas.
+func (p *PostgresTareaStore) Stats() (ss.Estadisticas, error) {
+	tareas, err := p.List("", "")
+	if err != nil {
+		return ss.Estadisticas{}, err
+	}
+
+	stats := ss.Estadisticas{Total: len(tareas)}
+	for _, tarea := range tareas {
+		switch tarea.Estado {
+		case "pendiente":
+			stats.Pendientes++
+		case "completado":
+			stats.Completadas++
+		}
+		switch tarea.Prioridad {
+		case "baja":
+			stats.PorPrioridad.Baja++
+		case "media":
+			stats.PorPrioridad.Media++
+		case "alta":
+			stats.PorPrioridad.Alta++
+		case "critica":
+			stats.PorPrioridad.Critica++
+		}
+	}
+
+	return stats, nil
+}
+
+func (p *PostgresTareaStore) cargarMetadata(tareaID string) (map[string]interface{}, error) {
+	filas, err := p.db.Query(`SELECT clave, valor FROM tareas_metadata WHERE tarea_id = $1`, tareaID)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer metadata: %w", err)
+	}
+	defer filas.Close()
+
+	metadata := make(map[string]interface{})
+	for filas.Next() {
+		var clave, valorJSON string
+		if err := filas.Scan(&clave, &valorJSON); err != nil {
+			return nil, fmt.Errorf("error al leer metadata: %w", err)
+		}
+		var valor interface{}
+		if err := json.Unmarshal([]byte(valorJSON), &valor); err != nil {
+			valor = valorJSON
+		}
+		metadata[clave] = valor
+	}
+
+	return metadata, filas.Err()
+}
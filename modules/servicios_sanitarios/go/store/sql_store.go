@@ -0,0 +1,26 @@
+package store
+
+// CampoURLFinal y CampoURLTarifasVigentes son los bits de changed_fields
+// en la tabla verificaciones, indicando qué campos cambiaron respecto al
+// resultado guardado anteriormente para el mismo target.
+const (
+	CampoURLFinal uint8 = 1 << iota
+	CampoURLTarifasVigentes
+)
+
+// calcularCambios compara el resultado previo (si existía) con el nuevo y
+// devuelve el bitmask de changed_fields a guardar junto a él.
+func calcularCambios(huboAnterior bool, urlFinalAnterior, urlTarifasAnterior, urlFinalNuevo, urlTarifasNuevo string) uint8 {
+	if !huboAnterior {
+		return CampoURLFinal | CampoURLTarifasVigentes
+	}
+
+	var cambios uint8
+	if urlFinalAnterior != urlFinalNuevo {
+		cambios |= CampoURLFinal
+	}
+	if urlTarifasAnterior != urlTarifasNuevo {
+		cambios |= CampoURLTarifasVigentes
+	}
+	return cambios
+}
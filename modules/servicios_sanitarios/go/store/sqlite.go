@@ -0,0 +1,454 @@
+// Package store provee implementaciones de ss.Store (tareas y
+// verificaciones de targets) respaldadas por una base de datos SQL, para
+// desplegar ServiciosSanitarios en varias instancias con estado durable
+// entre reinicios.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	ss "github.com/Geek-MD/Concierge/modules/servicios_sanitarios"
+)
+
+const esquemaSQLite = `
+CREATE TABLE IF NOT EXISTS tareas (
+	id TEXT PRIMARY KEY,
+	descripcion TEXT NOT NULL,
+	prioridad TEXT NOT NULL,
+	estado TEXT NOT NULL,
+	fecha_creacion TEXT NOT NULL,
+	fecha_completado TEXT
+);
+
+CREATE TABLE IF NOT EXISTS tareas_metadata (
+	tarea_id TEXT NOT NULL REFERENCES tareas(id) ON DELETE CASCADE,
+	clave TEXT NOT NULL,
+	valor TEXT NOT NULL,
+	PRIMARY KEY (tarea_id, clave)
+);
+
+CREATE TABLE IF NOT EXISTS targets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	nombre TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS verificaciones (
+	target_id INTEGER PRIMARY KEY REFERENCES targets(id) ON DELETE CASCADE,
+	url_original TEXT NOT NULL,
+	url_final TEXT NOT NULL,
+	url_tarifas_vigentes TEXT,
+	timestamp TEXT NOT NULL,
+	zona TEXT NOT NULL,
+	metadata TEXT,
+	changed_fields INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS historial (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	target_id INTEGER NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+	url_final TEXT NOT NULL,
+	url_tarifas_vigentes TEXT,
+	timestamp TEXT NOT NULL,
+	zona TEXT NOT NULL
+);
+`
+
+// SQLiteTareaStore implementa ss.Store (tareas y verificaciones de
+// targets) sobre una base SQLite local, vía database/sql y el driver
+// modernc.org/sqlite (sin cgo, para no requerir CGO_ENABLED=1 al
+// compilar ni en builds estáticos/cross-compiled).
+type SQLiteTareaStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteTareaStore abre (o crea) la base en rutaArchivo y aplica las
+// migraciones de esquema.
+func NewSQLiteTareaStore(rutaArchivo string) (*SQLiteTareaStore, error) {
+	db, err := sql.Open("sqlite", rutaArchivo)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir SQLite: %w", err)
+	}
+
+	if _, err := db.Exec(esquemaSQLite); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al migrar esquema SQLite: %w", err)
+	}
+
+	return &SQLiteTareaStore{db: db}, nil
+}
+
+// Close cierra la conexión a la base de datos.
+func (s *SQLiteTareaStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteTareaStore) idTarget(target string) (int64, error) {
+	if id, err := s.buscarIDTarget(target); err == nil {
+		return id, nil
+	}
+
+	if _, err := s.db.Exec(`INSERT OR IGNORE INTO targets (nombre) VALUES (?)`, target); err != nil {
+		return 0, fmt.Errorf("error al registrar target %q: %w", target, err)
+	}
+	return s.buscarIDTarget(target)
+}
+
+func (s *SQLiteTareaStore) buscarIDTarget(target string) (int64, error) {
+	var id int64
+	err := s.db.QueryRow(`SELECT id FROM targets WHERE nombre = ?`, target).Scan(&id)
+	return id, err
+}
+
+// SaveVerificacion actualiza el resultado vigente de target y reemplaza su
+// historial por el contenido de datos.Historial.
+func (s *SQLiteTareaStore) SaveVerificacion(ctx context.Context, target string, datos ss.DatosVerificacionSISS) error {
+	idTarget, err := s.idTarget(target)
+	if err != nil {
+		return err
+	}
+
+	var anterior ss.DatosVerificacionSISS
+	huboAnterior := s.db.QueryRowContext(ctx,
+		`SELECT url_final, url_tarifas_vigentes FROM verificaciones WHERE target_id = ?`, idTarget,
+	).Scan(&anterior.URLFinal, &anterior.URLTarifasVigentes) == nil
+	cambios := calcularCambios(huboAnterior, anterior.URLFinal, anterior.URLTarifasVigentes, datos.URLFinal, datos.URLTarifasVigentes)
+
+	metadata, err := json.Marshal(datos.Labels)
+	if err != nil {
+		return fmt.Errorf("error al serializar metadata: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO verificaciones (target_id, url_original, url_final, url_tarifas_vigentes, timestamp, zona, metadata, changed_fields)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(target_id) DO UPDATE SET
+			url_original = excluded.url_original,
+			url_final = excluded.url_final,
+			url_tarifas_vigentes = excluded.url_tarifas_vigentes,
+			timestamp = excluded.timestamp,
+			zona = excluded.zona,
+			metadata = excluded.metadata,
+			changed_fields = excluded.changed_fields`,
+		idTarget, datos.URLOriginal, datos.URLFinal, datos.URLTarifasVigentes, datos.Timestamp, datos.Zona, string(metadata), cambios,
+	)
+	if err != nil {
+		return fmt.Errorf("error al guardar verificación de %q: %w", target, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM historial WHERE target_id = ?`, idTarget); err != nil {
+		return fmt.Errorf("error al limpiar historial de %q: %w", target, err)
+	}
+	for _, entrada := range datos.Historial {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO historial (target_id, url_final, url_tarifas_vigentes, timestamp, zona) VALUES (?, ?, ?, ?, ?)`,
+			idTarget, entrada.URLFinal, entrada.URLTarifasVigentes, entrada.Timestamp, entrada.Zona,
+		); err != nil {
+			return fmt.Errorf("error al guardar historial de %q: %w", target, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LoadLatest devuelve el resultado vigente de target junto con su historial.
+func (s *SQLiteTareaStore) LoadLatest(ctx context.Context, target string) (ss.DatosVerificacionSISS, bool, error) {
+	idTarget, err := s.buscarIDTarget(target)
+	if err == sql.ErrNoRows {
+		return ss.DatosVerificacionSISS{}, false, nil
+	}
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al buscar target %q: %w", target, err)
+	}
+
+	var datos ss.DatosVerificacionSISS
+	var metadata sql.NullString
+	err = s.db.QueryRowContext(ctx,
+		`SELECT url_original, url_final, url_tarifas_vigentes, timestamp, zona, metadata FROM verificaciones WHERE target_id = ?`, idTarget,
+	).Scan(&datos.URLOriginal, &datos.URLFinal, &datos.URLTarifasVigentes, &datos.Timestamp, &datos.Zona, &metadata)
+	if err == sql.ErrNoRows {
+		return ss.DatosVerificacionSISS{}, false, nil
+	}
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al leer verificación de %q: %w", target, err)
+	}
+	datos.Verificado = true
+
+	if metadata.Valid && metadata.String != "" {
+		if err := json.Unmarshal([]byte(metadata.String), &datos.Labels); err != nil {
+			return ss.DatosVerificacionSISS{}, false, fmt.Errorf("error al deserializar metadata de %q: %w", target, err)
+		}
+	}
+
+	historial, err := s.cargarHistorialVerificacion(ctx, idTarget)
+	if err != nil {
+		return ss.DatosVerificacionSISS{}, false, err
+	}
+	datos.Historial = historial
+
+	return datos, true, nil
+}
+
+// AppendHistorial agrega entrada al historial de target, sin tocar su
+// resultado vigente.
+func (s *SQLiteTareaStore) AppendHistorial(ctx context.Context, target string, entrada ss.EntradaHistorial) error {
+	idTarget, err := s.idTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO historial (target_id, url_final, url_tarifas_vigentes, timestamp, zona) VALUES (?, ?, ?, ?, ?)`,
+		idTarget, entrada.URLFinal, entrada.URLTarifasVigentes, entrada.Timestamp, entrada.Zona,
+	); err != nil {
+		return fmt.Errorf("error al guardar historial de %q: %w", target, err)
+	}
+	return nil
+}
+
+func (s *SQLiteTareaStore) cargarHistorialVerificacion(ctx context.Context, idTarget int64) ([]ss.EntradaHistorial, error) {
+	filas, err := s.db.QueryContext(ctx,
+		`SELECT url_final, url_tarifas_vigentes, timestamp, zona FROM historial WHERE target_id = ? ORDER BY id`, idTarget,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer historial: %w", err)
+	}
+	defer filas.Close()
+
+	var historial []ss.EntradaHistorial
+	for filas.Next() {
+		var entrada ss.EntradaHistorial
+		if err := filas.Scan(&entrada.URLFinal, &entrada.URLTarifasVigentes, &entrada.Timestamp, &entrada.Zona); err != nil {
+			return nil, fmt.Errorf("error al leer historial: %w", err)
+		}
+		historial = append(historial, entrada)
+	}
+	return historial, filas.Err()
+}
+
+// Add inserta tarea y su metadata en sendas tablas, dentro de una transacción.
+func (s *SQLiteTareaStore) Add(tarea ss.Tarea) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fechaCompletado interface{}
+	if tarea.FechaCompletado != nil {
+		fechaCompletado = tarea.FechaCompletado.Format(time.RFC3339)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO tareas (id, descripcion, prioridad, estado, fecha_creacion, fecha_completado) VALUES (?, ?, ?, ?, ?, ?)`,
+		tarea.ID, tarea.Descripcion, tarea.Prioridad, tarea.Estado, tarea.FechaCreacion.Format(time.RFC3339), fechaCompletado,
+	)
+	if err != nil {
+		return fmt.Errorf("error al insertar tarea: %w", err)
+	}
+
+	for clave, valor := range tarea.Metadata {
+		valorJSON, err := json.Marshal(valor)
+		if err != nil {
+			return fmt.Errorf("error al serializar metadata %q: %w", clave, err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO tareas_metadata (tarea_id, clave, valor) VALUES (?, ?, ?)`,
+			tarea.ID, clave, string(valorJSON),
+		); err != nil {
+			return fmt.Errorf("error al insertar metadata %q: %w", clave, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get busca una tarea por ID.
+func (s *SQLiteTareaStore) Get(id string) (ss.Tarea, bool, error) {
+	tarea, encontrada, err := s.escanearTarea(
+		`SELECT id, descripcion, prioridad, estado, fecha_creacion, fecha_completado FROM tareas WHERE id = ?`, id,
+	)
+	if err != nil || !encontrada {
+		return ss.Tarea{}, encontrada, err
+	}
+
+	metadata, err := s.cargarMetadata(id)
+	if err != nil {
+		return ss.Tarea{}, false, err
+	}
+	tarea.Metadata = metadata
+
+	return tarea, true, nil
+}
+
+// List devuelve las tareas que matchean los filtros dados (vacíos = sin filtrar).
+func (s *SQLiteTareaStore) List(filtroEstado, filtroPrioridad string) ([]ss.Tarea, error) {
+	consulta := `SELECT id, descripcion, prioridad, estado, fecha_creacion, fecha_completado FROM tareas WHERE 1=1`
+	var args []interface{}
+
+	if filtroEstado != "" {
+		consulta += " AND estado = ?"
+		args = append(args, filtroEstado)
+	}
+	if filtroPrioridad != "" {
+		consulta += " AND prioridad = ?"
+		args = append(args, filtroPrioridad)
+	}
+
+	filas, err := s.db.Query(consulta, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar tareas: %w", err)
+	}
+	defer filas.Close()
+
+	var resultado []ss.Tarea
+	for filas.Next() {
+		tarea, err := escanearFilaTarea(filas)
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := s.cargarMetadata(tarea.ID)
+		if err != nil {
+			return nil, err
+		}
+		tarea.Metadata = metadata
+		resultado = append(resultado, tarea)
+	}
+
+	return resultado, filas.Err()
+}
+
+// Complete marca como completada la tarea con el ID dado.
+func (s *SQLiteTareaStore) Complete(id string, momento time.Time) (bool, error) {
+	res, err := s.db.Exec(
+		`UPDATE tareas SET estado = 'completado', fecha_completado = ? WHERE id = ?`,
+		momento.Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error al completar tarea: %w", err)
+	}
+
+	filas, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	return filas > 0, nil
+}
+
+// Count devuelve el número total de tareas almacenadas.
+func (s *SQLiteTareaStore) Count() (int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM tareas`).Scan(&total); err != nil {
+		return 0, fmt.Errorf("error al contar tareas: %w", err)
+	}
+	return total, nil
+}
+
+// Stats calcula los conteos de ss.Estadisticas a partir de la tabla de tareas.
+func (s *SQLiteTareaStore) Stats() (ss.Estadisticas, error) {
+	tareas, err := s.List("", "")
+	if err != nil {
+		return ss.Estadisticas{}, err
+	}
+
+	stats := ss.Estadisticas{Total: len(tareas)}
+	for _, tarea := range tareas {
+		switch tarea.Estado {
+		case "pendiente":
+			stats.Pendientes++
+		case "completado":
+			stats.Completadas++
+		}
+		switch tarea.Prioridad {
+		case "baja":
+			stats.PorPrioridad.Baja++
+		case "media":
+			stats.PorPrioridad.Media++
+		case "alta":
+			stats.PorPrioridad.Alta++
+		case "critica":
+			stats.PorPrioridad.Critica++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *SQLiteTareaStore) escanearTarea(consulta string, args ...interface{}) (ss.Tarea, bool, error) {
+	fila := s.db.QueryRow(consulta, args...)
+
+	var tarea ss.Tarea
+	var fechaCreacion string
+	var fechaCompletado sql.NullString
+
+	err := fila.Scan(&tarea.ID, &tarea.Descripcion, &tarea.Prioridad, &tarea.Estado, &fechaCreacion, &fechaCompletado)
+	if err == sql.ErrNoRows {
+		return ss.Tarea{}, false, nil
+	}
+	if err != nil {
+		return ss.Tarea{}, false, fmt.Errorf("error al leer tarea: %w", err)
+	}
+
+	tarea.FechaCreacion, _ = time.Parse(time.RFC3339, fechaCreacion)
+	if fechaCompletado.Valid {
+		t, _ := time.Parse(time.RFC3339, fechaCompletado.String)
+		tarea.FechaCompletado = &t
+	}
+
+	return tarea, true, nil
+}
+
+// escanearFilaTarea escanea una fila de un *sql.Rows en una ss.Tarea
+// (sin su metadata, que se carga aparte).
+func escanearFilaTarea(filas *sql.Rows) (ss.Tarea, error) {
+	var tarea ss.Tarea
+	var fechaCreacion string
+	var fechaCompletado sql.NullString
+
+	if err := filas.Scan(&tarea.ID, &tarea.Descripcion, &tarea.Prioridad, &tarea.Estado, &fechaCreacion, &fechaCompletado); err != nil {
+		return ss.Tarea{}, fmt.Errorf("error al leer tarea: %w", err)
+	}
+
+	tarea.FechaCreacion, _ = time.Parse(time.RFC3339, fechaCreacion)
+	if fechaCompletado.Valid {
+		t, _ := time.Parse(time.RFC3339, fechaCompletado.String)
+		tarea.FechaCompletado = &t
+	}
+
+	return tarea, nil
+}
+
+func (s *SQLiteTareaStore) cargarMetadata(tareaID string) (map[string]interface{}, error) {
+	filas, err := s.db.Query(`SELECT clave, valor FROM tareas_metadata WHERE tarea_id = ?`, tareaID)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer metadata: %w", err)
+	}
+	defer filas.Close()
+
+	metadata := make(map[string]interface{})
+	for filas.Next() {
+		var clave, valorJSON string
+		if err := filas.Scan(&clave, &valorJSON); err != nil {
+			return nil, fmt.Errorf("error al leer metadata: %w", err)
+		}
+		var valor interface{}
+		if err := json.Unmarshal([]byte(valorJSON), &valor); err != nil {
+			valor = valorJSON
+		}
+		metadata[clave] = valor
+	}
+
+	return metadata, filas.Err()
+}
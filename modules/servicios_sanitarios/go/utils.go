@@ -1,9 +1,9 @@
 package servicios_sanitarios
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,7 +11,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/net/html"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GenerateID genera un ID único para identificar elementos del sistema
@@ -70,22 +71,50 @@ func FormatearDuracion(inicio time.Time, fin *time.Time) string {
 	return strings.Join(partes, " ")
 }
 
-// VerificarRedireccionURL verifica la URL a la que redirecciona una página web
+// VerificarRedireccionURL verifica la URL a la que redirecciona una página web.
+//
+// Deprecated: usa VerificarRedireccionURLContext con un context.WithTimeout,
+// que permite cancelar la operación desde el llamador.
 func VerificarRedireccionURL(url string, timeout int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return VerificarRedireccionURLContext(ctx, url)
+}
+
+// VerificarRedireccionURLContext es la variante de VerificarRedireccionURL
+// que acepta un context.Context para cancelación, en lugar de un timeout
+// fijo. Se cancela tanto si el contexto expira durante la solicitud como
+// durante el seguimiento de la cadena de redirecciones.
+func VerificarRedireccionURLContext(ctx context.Context, url string) (string, error) {
+	ctx, span := tracer.Start(ctx, "http.redirect_chain", trace.WithAttributes(attribute.String("url.original", url)))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error al construir la solicitud: %w", err)
+	}
+
 	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return nil // Permitir redirecciones
 		},
 	}
 
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
+		span.RecordError(err)
 		return "", fmt.Errorf("error al verificar redirección: %w", err)
 	}
 	defer resp.Body.Close()
 
-	return resp.Request.URL.String(), nil
+	urlFinal := resp.Request.URL.String()
+	span.SetAttributes(
+		attribute.String("url.final", urlFinal),
+		attribute.Int("http.status_code", resp.StatusCode),
+	)
+
+	return urlFinal, nil
 }
 
 // GuardarJSON guarda datos en un archivo JSON
@@ -126,76 +155,6 @@ func CargarJSON(rutaArchivo string, datos interface{}) error {
 	return nil
 }
 
-// ExtraerURLPorTexto extrae la URL de un enlace en una página HTML buscando por el texto del enlace
-func ExtraerURLPorTexto(url, textoBuscar string, timeout int) (string, error) {
-	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return "", fmt.Errorf("error al obtener página: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("código de estado HTTP: %d", resp.StatusCode)
-	}
-
-	// Parsear HTML
-	doc, err := html.Parse(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("error al parsear HTML: %w", err)
-	}
-
-	// Buscar enlace recursivamente
-	var resultado string
-	var buscarEnlace func(*html.Node)
-	buscarEnlace = func(n *html.Node) {
-		if n.Type == html.ElementNode && n.Data == "a" {
-			// Obtener el texto del enlace
-			var textoEnlace string
-			var obtenerTexto func(*html.Node)
-			obtenerTexto = func(node *html.Node) {
-				if node.Type == html.TextNode {
-					textoEnlace += node.Data
-				}
-				for c := node.FirstChild; c != nil; c = c.NextSibling {
-					obtenerTexto(c)
-				}
-			}
-			obtenerTexto(n)
-
-			// Verificar si contiene el texto buscado (case insensitive)
-			if strings.Contains(strings.ToLower(textoEnlace), strings.ToLower(textoBuscar)) {
-				// Obtener el atributo href
-				for _, attr := range n.Attr {
-					if attr.Key == "href" {
-						resultado = resolverURLAbsoluta(url, attr.Val)
-						return
-					}
-				}
-			}
-		}
-
-		// Continuar búsqueda recursiva
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			if resultado != "" {
-				return
-			}
-			buscarEnlace(c)
-		}
-	}
-
-	buscarEnlace(doc)
-
-	if resultado == "" {
-		return "", fmt.Errorf("no se encontró enlace con el texto: %s", textoBuscar)
-	}
-
-	return resultado, nil
-}
-
 // resolverURLAbsoluta convierte una URL relativa en absoluta
 func resolverURLAbsoluta(baseURL, href string) string {
 	// Si ya es absoluta, retornarla
@@ -0,0 +1,46 @@
+package servicios_sanitarios
+
+import "context"
+
+// Store generaliza TareaStore agregando el almacenamiento de
+// verificaciones de targets (el mismo formato que usan VerificarSISSContext
+// y Verifier), de modo que tareas y verificaciones puedan compartir un
+// mismo backend durable en lugar de un archivo JSON por target. Las
+// implementaciones SQL viven en el subpaquete store, junto a las de
+// TareaStore.
+type Store interface {
+	TareaStore
+
+	// SaveVerificacion guarda (o reemplaza) el resultado vigente de target.
+	SaveVerificacion(ctx context.Context, target string, datos DatosVerificacionSISS) error
+	// LoadLatest devuelve el último resultado guardado para target,
+	// incluido su historial. ok es false si nunca se guardó uno.
+	LoadLatest(ctx context.Context, target string) (datos DatosVerificacionSISS, ok bool, err error)
+	// AppendHistorial agrega entrada al historial de cambios de target,
+	// independientemente de su resultado vigente.
+	AppendHistorial(ctx context.Context, target string, entrada EntradaHistorial) error
+}
+
+// WithStore reemplaza el backend de tareas por store, que además sirve de
+// almacenamiento de verificaciones para VerificarSISSContext en lugar de
+// CargarJSON/GuardarJSON sobre un archivo.
+func WithStore(store Store) Option {
+	return func(s *ServiciosSanitarios) {
+		s.store = store
+		s.verificaciones = store
+	}
+}
+
+// ObtenerVerificacion devuelve el último resultado guardado para target
+// (incluido su historial), siguiendo la misma resolución Store-o-archivo
+// que VerificarSISSContext: si el módulo se configuró con WithStore, lee
+// de ahí; si no, interpreta target como la ruta del archivo JSON que
+// escribe VerificarSISSContext. ok es false si nunca se guardó uno.
+func (s *ServiciosSanitarios) ObtenerVerificacion(ctx context.Context, target string) (datos DatosVerificacionSISS, ok bool, err error) {
+	if s.verificaciones != nil {
+		return s.verificaciones.LoadLatest(ctx, target)
+	}
+
+	err = CargarJSON(target, &datos)
+	return datos, err == nil, nil
+}
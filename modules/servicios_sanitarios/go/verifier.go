@@ -0,0 +1,349 @@
+package servicios_sanitarios
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/config"
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/extract"
+)
+
+// ResultadoVerificacion es el resultado de verificar un target de un
+// config.ScrapeConfig. Generaliza ResultadoVerificacionSISS a cualquier
+// target, agregando Target y Labels para que los consumidores puedan
+// filtrar o agrupar resultados de varias fuentes.
+type ResultadoVerificacion struct {
+	Target             string            `json:"target"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	Exito              bool              `json:"exito"`
+	URLOriginal        string            `json:"url_original"`
+	URLFinal           string            `json:"url_final,omitempty"`
+	URLTarifasVigentes string            `json:"url_tarifas_vigentes,omitempty"`
+	// Extraccion registra qué regla de config.Target.ReglasEfectivas
+	// produjo URLTarifasVigentes y el texto crudo que coincidió, para
+	// poder auditar el resultado sin tener que reproducir la cadena de
+	// reglas. Queda nil si la extracción falló.
+	Extraccion   *extract.Resultado `json:"extraccion,omitempty"`
+	Timestamp    string             `json:"timestamp"`
+	Zona         string             `json:"zona"`
+	Archivo      string             `json:"archivo,omitempty"`
+	Guardado     bool               `json:"guardado"`
+	EsPrimeraVez bool               `json:"es_primera_vez"`
+	Cambios      map[string]bool    `json:"cambios,omitempty"`
+	Mensaje      string             `json:"mensaje"`
+	Error        string             `json:"error,omitempty"`
+}
+
+// Verifier ejecuta las reglas de un config.ScrapeConfig contra sus
+// targets, reutilizando el mismo mecanismo de detección de cambios e
+// historial (DatosVerificacionSISS) que VerificarSISSContext.
+type Verifier struct {
+	config    config.ScrapeConfig
+	extractor *extract.Extractor
+	reloj     Clock
+	zona      *time.Location
+	logger    *slog.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+// VerifierOption configura un Verifier en su construcción.
+type VerifierOption func(*Verifier)
+
+// WithVerifierClock reemplaza el reloj del sistema del Verifier,
+// típicamente para hacer determinista un test.
+func WithVerifierClock(reloj Clock) VerifierOption {
+	return func(v *Verifier) {
+		v.reloj = reloj
+	}
+}
+
+// WithVerifierZona configura la zona horaria en la que el Verifier
+// expresa los timestamps de sus resultados. Si nombre no es una zona
+// IANA válida, se conserva UTC.
+func WithVerifierZona(nombre string) VerifierOption {
+	return func(v *Verifier) {
+		loc, err := time.LoadLocation(nombre)
+		if err != nil {
+			return
+		}
+		v.zona = loc
+	}
+}
+
+// WithVerifierLogger reemplaza el *slog.Logger que el Verifier usa para
+// narrar el progreso de cada target. Sin esta opción se usa
+// slog.Default().
+func WithVerifierLogger(logger *slog.Logger) VerifierOption {
+	return func(v *Verifier) {
+		v.logger = logger
+	}
+}
+
+// WithVerifierTracer reemplaza el trace.Tracer que el Verifier usa para
+// instrumentar verificarTarget. Sin esta opción se usa el tracer por
+// defecto del paquete.
+func WithVerifierTracer(t trace.Tracer) VerifierOption {
+	return func(v *Verifier) {
+		v.tracer = t
+	}
+}
+
+// WithVerifierStore reemplaza CargarJSON/GuardarJSON sobre
+// target.Almacenamiento por store, el mismo backend durable que WithStore
+// configura para VerificarSISSContext. Sin esta opción, cada target se
+// sigue leyendo y guardando como un archivo JSON independiente.
+func WithVerifierStore(store Store) VerifierOption {
+	return func(v *Verifier) {
+		v.store = store
+	}
+}
+
+// NewVerifier crea un Verifier para cfg. Los targets que no pasaron por
+// config.CargarScrapeConfig (p.ej. construidos a mano en un test) reciben
+// aquí sus valores de Intervalo/Timeout/Reintentos por defecto.
+func NewVerifier(cfg config.ScrapeConfig, opts ...VerifierOption) *Verifier {
+	for i := range cfg.Targets {
+		cfg.Targets[i].AplicarValoresPorDefecto()
+	}
+
+	v := &Verifier{
+		config:    cfg,
+		extractor: extract.NewExtractor(),
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.reloj == nil {
+		v.reloj = relojSistema{}
+	}
+	if v.zona == nil {
+		v.zona = time.UTC
+	}
+	if v.logger == nil {
+		v.logger = slog.Default()
+	}
+	if v.tracer == nil {
+		v.tracer = tracer
+	}
+
+	return v
+}
+
+// Run verifica todos los targets de la configuración y devuelve un
+// ResultadoVerificacion por cada uno, en el mismo orden en que aparecen
+// en el ScrapeConfig.
+func (v *Verifier) Run(ctx context.Context) []ResultadoVerificacion {
+	resultados := make([]ResultadoVerificacion, 0, len(v.config.Targets))
+
+	for _, target := range v.config.Targets {
+		resultados = append(resultados, v.verificarTarget(ctx, target))
+	}
+
+	return resultados
+}
+
+// VerificarTarget ejecuta la verificación de un único target, sin recorrer
+// el resto de v.config.Targets. Lo usa scheduler.Daemon, que agenda cada
+// target con su propio cron y necesita invocarlos de forma independiente.
+func (v *Verifier) VerificarTarget(ctx context.Context, target config.Target) ResultadoVerificacion {
+	return v.verificarTarget(ctx, target)
+}
+
+// ObtenerDatos devuelve los últimos datos guardados para ruta (el
+// almacenamiento de un target), siguiendo la misma resolución Store-o-archivo
+// que verificarTarget: si el Verifier se creó con WithVerifierStore, lee de
+// ahí; si no, interpreta ruta como la ruta del archivo JSON que escribe
+// verificarTarget. Lo usa scheduler.Daemon para leer el resultado recién
+// guardado antes de notificarlo. ok es false si nunca se guardó uno; a
+// diferencia de ok, err solo es no-nil ante un fallo real de lectura (p.ej.
+// un archivo corrupto), no ante la ausencia esperada de datos previos.
+func (v *Verifier) ObtenerDatos(ctx context.Context, ruta string) (datos DatosVerificacionSISS, ok bool, err error) {
+	if v.store != nil {
+		return v.store.LoadLatest(ctx, ruta)
+	}
+
+	if err := CargarJSON(ruta, &datos); err != nil {
+		return DatosVerificacionSISS{}, false, err
+	}
+	return datos, true, nil
+}
+
+func (v *Verifier) verificarTarget(ctx context.Context, target config.Target) (resultado ResultadoVerificacion) {
+	ctx, span := v.tracer.Start(ctx, "VerificarTarget", trace.WithAttributes(
+		attribute.String("target", target.Nombre),
+		attribute.String("url.original", target.URL),
+	))
+	defer func() {
+		span.SetAttributes(
+			attribute.String("url.final", resultado.URLFinal),
+			attribute.StringSlice("changed_fields", camposCambiados(resultado.Cambios)),
+		)
+		if resultado.Error != "" {
+			span.SetStatus(codes.Error, resultado.Error)
+		}
+		span.End()
+	}()
+
+	timestamp := v.reloj.Now().In(v.zona)
+
+	ctxTarget, cancel := context.WithTimeout(ctx, target.Timeout)
+	defer cancel()
+
+	urlFinal, err := v.verificarConReintentos(ctxTarget, target)
+	if err != nil {
+		v.logger.WarnContext(ctx, "no se pudo verificar el target", "target", target.Nombre, "error", err)
+		return ResultadoVerificacion{
+			Target:      target.Nombre,
+			Labels:      target.Labels,
+			URLOriginal: target.URL,
+			Timestamp:   FormatTimestamp(timestamp),
+			Zona:        v.zona.String(),
+			Error:       fmt.Sprintf("no se pudo verificar el target: %v", err),
+		}
+	}
+	v.logger.DebugContext(ctx, "redirección resuelta", "target", target.Nombre, "url.final", urlFinal)
+
+	// La ausencia del enlace de interés no es fatal: se reporta como
+	// cadena vacía y el target sigue pudiendo detectar cambios de URLFinal.
+	var extraccion *extract.Resultado
+	urlInteres, resultadoExtraccion, err := v.extraerURLInteres(ctxTarget, target, urlFinal)
+	if err == nil {
+		extraccion = &resultadoExtraccion
+		v.logger.DebugContext(ctx, "url de interés extraída", "target", target.Nombre, "url.interes", urlInteres)
+	} else {
+		urlInteres = ""
+		v.logger.DebugContext(ctx, "no se pudo extraer la url de interés", "target", target.Nombre, "error", err)
+	}
+
+	datosPrevios, okPrevios, errCarga := v.ObtenerDatos(ctx, target.Almacenamiento)
+	esPrimeraVez := !okPrevios || errCarga != nil
+
+	_, diffSpan := v.tracer.Start(ctx, "diff")
+	urlFinalCambio := false
+	urlInteresCambio := false
+	if !esPrimeraVez {
+		urlFinalCambio = datosPrevios.URLFinal != urlFinal
+		urlInteresCambio = datosPrevios.URLTarifasVigentes != urlInteres
+	}
+	hayCambios := esPrimeraVez || urlFinalCambio || urlInteresCambio
+	diffSpan.SetAttributes(attribute.StringSlice("changed_fields", camposCambiados(map[string]bool{
+		"url_final":            urlFinalCambio,
+		"url_tarifas_vigentes": urlInteresCambio,
+	})))
+	diffSpan.End()
+
+	guardado := false
+	if hayCambios {
+		historial := []EntradaHistorial{}
+		if !esPrimeraVez && datosPrevios.Historial != nil {
+			historial = datosPrevios.Historial
+		}
+		if !esPrimeraVez {
+			historial = append(historial, EntradaHistorial{
+				URLFinal:           datosPrevios.URLFinal,
+				URLTarifasVigentes: datosPrevios.URLTarifasVigentes,
+				Timestamp:          datosPrevios.Timestamp,
+				Zona:               datosPrevios.Zona,
+			})
+		}
+
+		datos := DatosVerificacionSISS{
+			URLOriginal:        target.URL,
+			URLFinal:           urlFinal,
+			URLTarifasVigentes: urlInteres,
+			Timestamp:          FormatTimestamp(timestamp),
+			Zona:               v.zona.String(),
+			Verificado:         true,
+			Historial:          historial,
+			Labels:             target.Labels,
+			Extraccion:         extraccion,
+		}
+
+		_, storeSpan := v.tracer.Start(ctx, "store.write", trace.WithAttributes(attribute.String("store.path", target.Almacenamiento)))
+		var errGuardar error
+		if v.store != nil {
+			errGuardar = v.store.SaveVerificacion(ctx, target.Almacenamiento, datos)
+		} else {
+			errGuardar = GuardarJSON(datos, target.Almacenamiento)
+		}
+		if errGuardar == nil {
+			guardado = true
+		} else {
+			storeSpan.RecordError(errGuardar)
+			v.logger.WarnContext(ctx, "no se pudieron guardar los datos de verificación", "target", target.Nombre, "error", errGuardar)
+		}
+		storeSpan.End()
+	}
+
+	mensaje := "Sin cambios, no se guardó"
+	if esPrimeraVez {
+		mensaje = "Primera verificación guardada"
+	} else if hayCambios {
+		mensaje = "Cambios detectados y guardados"
+	}
+
+	return ResultadoVerificacion{
+		Target:             target.Nombre,
+		Labels:             target.Labels,
+		Exito:              true,
+		URLOriginal:        target.URL,
+		URLFinal:           urlFinal,
+		URLTarifasVigentes: urlInteres,
+		Extraccion:         extraccion,
+		Timestamp:          FormatTimestamp(timestamp),
+		Zona:               v.zona.String(),
+		Archivo:            target.Almacenamiento,
+		Guardado:           guardado,
+		EsPrimeraVez:       esPrimeraVez,
+		Cambios: map[string]bool{
+			"url_final":            urlFinalCambio,
+			"url_tarifas_vigentes": urlInteresCambio,
+		},
+		Mensaje: mensaje,
+	}
+}
+
+// verificarConReintentos reintenta VerificarRedireccionURLContext hasta
+// target.Reintentos veces, devolviendo el primer éxito o el último error.
+func (v *Verifier) verificarConReintentos(ctx context.Context, target config.Target) (string, error) {
+	intentos := target.Reintentos
+	if intentos < 1 {
+		intentos = 1
+	}
+
+	var ultimoErr error
+	for i := 0; i < intentos; i++ {
+		urlFinal, err := VerificarRedireccionURLContext(ctx, target.URL)
+		if err == nil {
+			return urlFinal, nil
+		}
+		ultimoErr = err
+	}
+
+	return "", ultimoErr
+}
+
+// extraerURLInteres aplica la cadena de reglas de extracción de target
+// (target.ReglasEfectivas, ver el paquete extract) sobre urlFinal y
+// devuelve el resultado de la última regla de la cadena.
+func (v *Verifier) extraerURLInteres(ctx context.Context, target config.Target, urlFinal string) (string, extract.Resultado, error) {
+	reglas := target.ReglasEfectivas()
+	if len(reglas) == 0 {
+		return "", extract.Resultado{}, fmt.Errorf("target %q sin regla de extracción", target.Nombre)
+	}
+
+	resultado, err := v.extractor.AplicarContext(ctx, urlFinal, reglas)
+	if err != nil {
+		return "", extract.Resultado{}, err
+	}
+
+	return resultado.Valor, resultado, nil
+}
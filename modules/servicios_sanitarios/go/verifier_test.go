@@ -0,0 +1,89 @@
+package servicios_sanitarios
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Geek-MD/Concierge/modules/servicios_sanitarios/config"
+)
+
+func TestVerifierRunDetectaCambios(t *testing.T) {
+	href := "/tarifas-v1.html"
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><a href="` + href + `">Tarifas vigentes</a></body></html>`))
+	}))
+	defer servidor.Close()
+
+	almacenamiento := filepath.Join(t.TempDir(), "target.json")
+	cfg := config.ScrapeConfig{Targets: []config.Target{
+		{
+			Nombre:         "demo",
+			URL:            servidor.URL,
+			Extraccion:     config.ReglaExtraccion{Selector: `a:contains("Tarifas vigentes")`},
+			Almacenamiento: almacenamiento,
+			Labels:         map[string]string{"region": "metropolitana"},
+		},
+	}}
+
+	verifier := NewVerifier(cfg)
+
+	primeros := verifier.Run(context.Background())
+	if len(primeros) != 1 {
+		t.Fatalf("se esperaba 1 resultado, hay %d", len(primeros))
+	}
+	if !primeros[0].EsPrimeraVez || !primeros[0].Guardado {
+		t.Errorf("la primera verificación debe marcarse como primera vez y quedar guardada: %+v", primeros[0])
+	}
+	if primeros[0].Labels["region"] != "metropolitana" {
+		t.Errorf("Labels no se propagó al resultado: %+v", primeros[0].Labels)
+	}
+
+	segundos := verifier.Run(context.Background())
+	if segundos[0].EsPrimeraVez || segundos[0].Guardado {
+		t.Errorf("sin cambios, la segunda verificación no debe guardarse de nuevo: %+v", segundos[0])
+	}
+
+	href = "/tarifas-v2.html"
+	terceros := verifier.Run(context.Background())
+	if !terceros[0].Cambios["url_tarifas_vigentes"] || !terceros[0].Guardado {
+		t.Errorf("un cambio de enlace debe detectarse y guardarse: %+v", terceros[0])
+	}
+
+	var datos DatosVerificacionSISS
+	if err := CargarJSON(almacenamiento, &datos); err != nil {
+		t.Fatalf("error al leer historial guardado: %v", err)
+	}
+	if datos.Labels["region"] != "metropolitana" {
+		t.Errorf("Labels no se persistió en el JSON: %+v", datos.Labels)
+	}
+	if len(datos.Historial) != 1 {
+		t.Errorf("debe haber 1 entrada de historial tras el cambio, hay %d", len(datos.Historial))
+	}
+}
+
+func TestVerifierRunConRegex(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`ver tarifas en /archivos/tarifas-2026.pdf para más información`))
+	}))
+	defer servidor.Close()
+
+	cfg := config.ScrapeConfig{Targets: []config.Target{
+		{
+			Nombre:         "demo-regex",
+			URL:            servidor.URL,
+			Extraccion:     config.ReglaExtraccion{Regex: `/archivos/tarifas-[0-9]+\.pdf`},
+			Almacenamiento: filepath.Join(t.TempDir(), "target.json"),
+		},
+	}}
+
+	resultados := NewVerifier(cfg).Run(context.Background())
+	if len(resultados) != 1 {
+		t.Fatalf("se esperaba 1 resultado, hay %d", len(resultados))
+	}
+	if resultados[0].URLTarifasVigentes != "/archivos/tarifas-2026.pdf" {
+		t.Errorf("URLTarifasVigentes esperado '/archivos/tarifas-2026.pdf', obtenido %q", resultados[0].URLTarifasVigentes)
+	}
+}